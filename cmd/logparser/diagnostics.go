@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/vhrboliveira/quake-log-parser-test/pkg/quakelog"
+)
+
+// listedDiagnostics is the number of anomalies of each log printed in full.
+const listedDiagnostics = 10
+
+// diagnosed runs parse with a collector of the anomalies of the log at path
+// added to options, then prints their summary to w.
+func diagnosed(w io.Writer, path string, options []quakelog.Option, parse func(options ...quakelog.Option) error) error {
+	diagnostics := &quakelog.Diagnostics{}
+	err := parse(append(slices.Clone(options), quakelog.OnDiagnostic(diagnostics.Add))...)
+	printDiagnostics(w, path, diagnostics)
+
+	return err
+}
+
+// printDiagnostics writes the number of anomalies of each kind found in the
+// log at path, followed by the first ones. Nothing is written for a log
+// without anomalies.
+func printDiagnostics(w io.Writer, path string, diagnostics *quakelog.Diagnostics) {
+	list := diagnostics.List()
+	if len(list) == 0 {
+		return
+	}
+
+	summary := diagnostics.Summary()
+	counts := make([]string, 0, len(summary))
+	for _, kind := range slices.Sorted(maps.Keys(summary)) {
+		counts = append(counts, fmt.Sprintf("%s: %d", kind, summary[kind]))
+	}
+
+	var b strings.Builder
+	noun := "anomalies"
+	if len(list) == 1 {
+		noun = "anomaly"
+	}
+	fmt.Fprintf(&b, "%s: %d %s (%s)\n", path, len(list), noun, strings.Join(counts, ", "))
+	for _, diagnostic := range list[:min(len(list), listedDiagnostics)] {
+		fmt.Fprintf(&b, "  line %d: %s: %q\n", diagnostic.Line, diagnostic.Reason, diagnostic.Text)
+	}
+	if len(list) > listedDiagnostics {
+		fmt.Fprintf(&b, "  and %d more\n", len(list)-listedDiagnostics)
+	}
+
+	// a single write keeps the summaries of logs parsed concurrently apart
+	io.WriteString(w, b.String())
+}
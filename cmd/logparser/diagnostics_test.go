@@ -0,0 +1,115 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vhrboliveira/quake-log-parser-test/internal/file"
+	"github.com/vhrboliveira/quake-log-parser-test/pkg/quakelog"
+)
+
+func TestPrintDiagnostics(t *testing.T) {
+	tests := []struct {
+		name        string
+		diagnostics []quakelog.Diagnostic
+		expected    string
+	}{
+		{
+			name:     "No anomalies",
+			expected: "",
+		},
+		{
+			name: "Single anomaly",
+			diagnostics: []quakelog.Diagnostic{
+				{Line: 3, Kind: quakelog.DIAGNOSTIC_UNKNOWN_LINE, Reason: "unknown entry Flag", Text: "  0:01 Flag: 2 0"},
+			},
+			expected: "games.log: 1 anomaly (unknown_line: 1)\n" +
+				"  line 3: unknown entry Flag: \"  0:01 Flag: 2 0\"\n",
+		},
+		{
+			name: "More anomalies than listed",
+			diagnostics: func() []quakelog.Diagnostic {
+				diagnostics := []quakelog.Diagnostic{{Line: 1, Kind: quakelog.DIAGNOSTIC_MALFORMED_LINE, Reason: "malformed", Text: "x"}}
+				for i := 2; i <= 12; i++ {
+					diagnostics = append(diagnostics, quakelog.Diagnostic{Line: i, Kind: quakelog.DIAGNOSTIC_UNKNOWN_LINE, Reason: "unknown", Text: "y"})
+				}
+				return diagnostics
+			}(),
+			expected: func() string {
+				expected := "games.log: 12 anomalies (malformed_line: 1, unknown_line: 11)\n" +
+					"  line 1: malformed: \"x\"\n"
+				for i := 2; i <= 10; i++ {
+					expected += fmt.Sprintf("  line %d: unknown: \"y\"\n", i)
+				}
+				return expected + "  and 2 more\n"
+			}(),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			diagnostics := &quakelog.Diagnostics{}
+			for _, diagnostic := range tc.diagnostics {
+				diagnostics.Add(diagnostic)
+			}
+
+			var out bytes.Buffer
+			printDiagnostics(&out, "games.log", diagnostics)
+
+			assert.Equal(t, tc.expected, out.String())
+		})
+	}
+}
+
+func TestRunStrict(t *testing.T) {
+	content, err := os.ReadFile("../../assets/test.log")
+	assert.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "games.log")
+	lines := strings.SplitAfter(string(content), "\n")
+	corrupted := strings.Join(lines[:2], "") + "10:01 Flag: 2 0: team_CTF_blueflag\n" + strings.Join(lines[2:], "")
+	assert.NoError(t, os.WriteFile(path, []byte(corrupted), 0o644))
+
+	assert.NoError(t, run(context.Background(), []string{path}), "Anomalies are skipped by default")
+	assert.FileExists(t, path+".json")
+	assert.NoError(t, os.Remove(path+".json"))
+
+	err = run(context.Background(), []string{"-strict", path})
+
+	assert.ErrorContains(t, err, `line 3: unknown entry Flag: "10:01 Flag: 2 0: team_CTF_blueflag"`)
+	assert.NoFileExists(t, path+".json")
+}
+
+func TestRunDiagnosticsSection(t *testing.T) {
+	content, err := os.ReadFile("../../assets/test.log")
+	assert.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "games.log")
+	lines := strings.SplitAfter(string(content), "\n")
+	corrupted := strings.Join(lines[:2], "") + "10:01 Flag: 2 0: team_CTF_blueflag\n" + strings.Join(lines[2:], "")
+	assert.NoError(t, os.WriteFile(path, []byte(corrupted), 0o644))
+
+	for _, args := range [][]string{{}, {"-diagnostics"}} {
+		assert.NoError(t, run(context.Background(), append(args, path)))
+
+		report, err := file.ReadReport(path + ".json")
+		assert.NoError(t, err)
+		if len(args) == 0 {
+			assert.Nil(t, report[0]["game_1"].Sections, "The anomalies are only reported with -diagnostics")
+			continue
+		}
+		diagnostics, ok := report[0]["game_1"].Sections["diagnostics"].([]any)
+		assert.True(t, ok)
+		kinds := make([]any, 0, len(diagnostics))
+		for _, diagnostic := range diagnostics {
+			kinds = append(kinds, diagnostic.(map[string]any)["kind"])
+		}
+		assert.Equal(t, []any{quakelog.DIAGNOSTIC_UNKNOWN_LINE, quakelog.DIAGNOSTIC_MISSING_SHUTDOWN}, kinds)
+	}
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/vhrboliveira/quake-log-parser-test/internal/batch"
+	"github.com/vhrboliveira/quake-log-parser-test/internal/diff"
+	"github.com/vhrboliveira/quake-log-parser-test/internal/file"
+	"github.com/vhrboliveira/quake-log-parser-test/pkg/quakelog"
+)
+
+// diffReports prints the differences between two reports. Each one is read
+// from a .json report file, or parsed from a log with the parser flags.
+func diffReports(ctx context.Context, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("diff", flag.ContinueOnError)
+	asJSON := flags.Bool("json", false, "print the differences as JSON")
+	parser := parserFlags(flags)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() != 2 {
+		return errors.New("expected the old and the new report. Ex: logparser diff assets/qgames.log.json assets/qgames.log")
+	}
+
+	options, err := parser()
+	if err != nil {
+		return err
+	}
+
+	reports := make([]quakelog.GameReport, 2)
+	for i, path := range flags.Args() {
+		if strings.HasSuffix(path, ".json") {
+			reports[i], err = file.ReadReport(path)
+		} else {
+			reports[i], err = batch.ParseFile(ctx, path, options...)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	changes := diff.Compare(reports[0], reports[1])
+	if *asJSON {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(changes)
+	}
+
+	return printChanges(out, changes)
+}
+
+func printChanges(out io.Writer, changes []diff.Match) error {
+	if len(changes) == 0 {
+		_, err := fmt.Fprintln(out, "No differences")
+		return err
+	}
+
+	var b strings.Builder
+	for _, match := range changes {
+		switch match.Change {
+		case diff.ADDED:
+			fmt.Fprintf(&b, "+ %s: added, %d kills\n", match.Game, match.TotalKills.New)
+			continue
+		case diff.REMOVED:
+			fmt.Fprintf(&b, "- %s: removed, %d kills\n", match.Game, match.TotalKills.Old)
+			continue
+		}
+
+		fmt.Fprintf(&b, "~ %s\n", match.Game)
+		if match.Map != nil {
+			fmt.Fprintf(&b, "    map: %s -> %s\n", match.Map.Old, match.Map.New)
+		}
+		if match.Integrity != nil {
+			fmt.Fprintf(&b, "    integrity: %s -> %s\n", match.Integrity.Old, match.Integrity.New)
+		}
+		if match.TotalKills.Old != match.TotalKills.New {
+			fmt.Fprintf(&b, "    total kills: %s\n", delta(match.TotalKills))
+		}
+		for _, player := range match.Players {
+			switch player.Change {
+			case diff.ADDED:
+				fmt.Fprintf(&b, "    + %s: %d\n", player.Name, player.Kills.New)
+			case diff.REMOVED:
+				fmt.Fprintf(&b, "    - %s: %d\n", player.Name, player.Kills.Old)
+			default:
+				fmt.Fprintf(&b, "    ~ %s: %s\n", player.Name, delta(player.Kills))
+			}
+		}
+		for _, means := range match.Means {
+			fmt.Fprintf(&b, "    %s: %s\n", means.Name, delta(means.Kills))
+		}
+	}
+	fmt.Fprintf(&b, "%d matches differ\n", len(changes))
+
+	_, err := io.WriteString(out, b.String())
+	return err
+}
+
+// delta formats a change of kills, like "3 -> 5 (+2)".
+func delta(kills diff.Delta[int]) string {
+	return fmt.Sprintf("%d -> %d (%+d)", kills.Old, kills.New, kills.New-kills.Old)
+}
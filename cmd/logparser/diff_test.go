@@ -0,0 +1,104 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vhrboliveira/quake-log-parser-test/internal/diff"
+)
+
+func TestDiffReports(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		expected string
+	}{
+		{
+			name:     "Identical",
+			args:     []string{"testdata/golden/test.json", "../../assets/test.log"},
+			expected: "No differences\n",
+		},
+		{
+			name: "Scoring change",
+			args: []string{"-kill-points", "2", "testdata/golden/test.json", "../../assets/test.log"},
+			expected: "~ game_2\n" +
+				"    ~ Isgalamido (ID 2): 3 -> 8 (+5)\n" +
+				"    ~ Dono da Bola (ID 3): 1 -> 2 (+1)\n" +
+				"    ~ Mocinha (ID 4): 1 -> 2 (+1)\n" +
+				"~ game_3\n" +
+				"    ~ Isgalamido (ID 2): 2 -> 5 (+3)\n" +
+				"    ~ Dono da Bola (ID 3): 1 -> 2 (+1)\n" +
+				"    ~ Oootsimo (ID 5): 4 -> 8 (+4)\n" +
+				"2 matches differ\n",
+		},
+		{
+			name: "Different logs",
+			args: []string{"testdata/golden/test.json", "testdata/golden/baseq3_tdm.json"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var out bytes.Buffer
+
+			err := diffReports(context.Background(), tc.args, &out)
+
+			assert.NoError(t, err)
+			if tc.expected != "" {
+				assert.Equal(t, tc.expected, out.String())
+				return
+			}
+			assert.Contains(t, out.String(), "matches differ")
+		})
+	}
+}
+
+func TestDiffReportsJSON(t *testing.T) {
+	var out bytes.Buffer
+
+	err := diffReports(context.Background(), []string{"-json", "-kill-points", "2", "testdata/golden/test.json", "../../assets/test.log"}, &out)
+	assert.NoError(t, err)
+
+	var changes []diff.Match
+	assert.NoError(t, json.Unmarshal(out.Bytes(), &changes))
+	assert.Len(t, changes, 2)
+	assert.Equal(t, "game_2", changes[0].Game)
+	assert.Equal(t, diff.Player{Name: "Isgalamido (ID 2)", Change: diff.CHANGED, Kills: diff.Delta[int]{Old: 3, New: 8}}, changes[0].Players[0])
+}
+
+func TestDiffReportsJSONStdout(t *testing.T) {
+	// both reports are parsed from logs, whose progress must not reach stdout
+	stdout := captureStdout(t, func() {
+		assert.NoError(t, run(context.Background(), []string{"diff", "-json", "-kill-points", "2", "../../assets/test.log", "../../assets/test.log"}))
+	})
+
+	var changes []diff.Match
+	assert.NoError(t, json.Unmarshal(stdout, &changes), "only the JSON is printed to stdout")
+	assert.Empty(t, changes)
+}
+
+func TestDiffReportsErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		args   []string
+		errMsg string
+	}{
+		{name: "One report", args: []string{"testdata/golden/test.json"}, errMsg: "expected the old and the new report"},
+		{name: "Missing report", args: []string{"testdata/golden/test.json", "missing.json"}, errMsg: "missing.json"},
+		{name: "Unknown order", args: []string{"-order", "kills", "a.json", "b.json"}, errMsg: `unknown player order "kills"`},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var out bytes.Buffer
+
+			err := diffReports(context.Background(), tc.args, &out)
+
+			assert.ErrorContains(t, err, tc.errMsg)
+			assert.Empty(t, out.String())
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/vhrboliveira/quake-log-parser-test/internal/batch"
+	"github.com/vhrboliveira/quake-log-parser-test/internal/file"
+	"github.com/vhrboliveira/quake-log-parser-test/pkg/quakelog"
+)
+
+// followLog tails the log at path until ctx is done, appending every finished
+// match as one JSON line to path + ".jsonl". The log is read from the
+// beginning on every run, so the file is rebuilt instead of appended to,
+// which keeps a restart from writing the same matches twice.
+func followLog(ctx context.Context, path string, options ...quakelog.Option) error {
+	output, err := os.OpenFile(path+".jsonl", os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("error creating file: %w", err)
+	}
+	defer output.Close()
+
+	// a failed write stops the reading like an interrupt, but is reported
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	encoder := json.NewEncoder(output)
+
+	onMatchEnd := func(match map[string]quakelog.MatchReport) {
+		for gameName, report := range match {
+			fmt.Printf("%s finished with %d kills\n", gameName, report.TotalKills)
+		}
+
+		if err := encoder.Encode(match); err != nil {
+			cancel(fmt.Errorf("error writing to file: %w", err))
+		}
+	}
+
+	err = batch.Pipeline{
+		Read:  []file.ReadOption{file.WithFollow()},
+		Parse: append(options, quakelog.OnMatchEnd(onMatchEnd)),
+	}.Run(ctx, path)
+	if err != nil {
+		return err
+	}
+
+	if err := context.Cause(ctx); err != nil && !errors.Is(err, context.Canceled) {
+		return err
+	}
+
+	return nil
+}
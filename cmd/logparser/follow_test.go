@@ -0,0 +1,120 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vhrboliveira/quake-log-parser-test/pkg/quakelog"
+)
+
+func TestFollowLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "games.log")
+	assert.NoError(t, os.WriteFile(path, []byte("0:00 InitGame: \\sv_floodProtect\\1\n"), 0o644))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	result := make(chan error, 1)
+	go func() {
+		result <- followLog(ctx, path)
+	}()
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+	assert.NoError(t, err)
+	_, err = f.WriteString("0:01 ClientUserinfoChanged: 2 n\\Player1\\t\\0\n" +
+		"0:02 Kill: 1022 2 22: <world> killed Player1 by MOD_TRIGGER_HURT\n" +
+		"0:03 ShutdownGame:\n")
+	assert.NoError(t, err)
+	assert.NoError(t, f.Close())
+
+	var match map[string]quakelog.MatchReport
+	assert.Eventually(t, func() bool {
+		content, err := os.Open(path + ".jsonl")
+		if err != nil {
+			return false
+		}
+		defer content.Close()
+
+		scanner := bufio.NewScanner(content)
+		return scanner.Scan() && json.Unmarshal(scanner.Bytes(), &match) == nil
+	}, 5*time.Second, 20*time.Millisecond)
+
+	assert.Equal(t, 1, match["game_1"].TotalKills)
+	assert.Equal(t, -1, match["game_1"].Kills["Player1 (ID 2)"])
+
+	cancel()
+
+	select {
+	case err := <-result:
+		assert.NoError(t, err)
+	case <-time.After(2 * time.Second):
+		t.Fatal("follow mode did not stop")
+	}
+}
+
+func TestFollowLogRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "games.log")
+	match := func(minute int) string {
+		return fmt.Sprintf("%d:00 InitGame: \\sv_floodProtect\\1\n", minute) +
+			fmt.Sprintf("%d:01 ClientUserinfoChanged: 2 n\\Player1\\t\\0\n", minute) +
+			fmt.Sprintf("%d:02 Kill: 1022 2 22: <world> killed Player1 by MOD_TRIGGER_HURT\n", minute) +
+			fmt.Sprintf("%d:03 ShutdownGame:\n", minute)
+	}
+	assert.NoError(t, os.WriteFile(path, []byte(match(0)), 0o644))
+
+	games := func() []string {
+		content, err := os.ReadFile(path + ".jsonl")
+		if err != nil {
+			return nil
+		}
+
+		names := make([]string, 0)
+		for _, line := range strings.Split(strings.TrimSuffix(string(content), "\n"), "\n") {
+			var match map[string]quakelog.MatchReport
+			if json.Unmarshal([]byte(line), &match) != nil {
+				return nil
+			}
+			for name := range match {
+				names = append(names, name)
+			}
+		}
+		return names
+	}
+
+	follow := func(wait func() bool) {
+		ctx, cancel := context.WithCancel(context.Background())
+		result := make(chan error, 1)
+		go func() {
+			result <- followLog(ctx, path)
+		}()
+
+		assert.Eventually(t, wait, 5*time.Second, 20*time.Millisecond)
+		cancel()
+		assert.NoError(t, <-result)
+	}
+
+	follow(func() bool { return len(games()) == 1 })
+
+	// the follower is restarted while the server keeps writing the log
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+	assert.NoError(t, err)
+	_, err = f.WriteString(match(1))
+	assert.NoError(t, err)
+	assert.NoError(t, f.Close())
+
+	follow(func() bool { return len(games()) == 2 })
+
+	assert.Equal(t, []string{"game_1", "game_2"}, games(), "The matches read before the restart are not written twice")
+}
+
+func TestRunFollowMultipleFiles(t *testing.T) {
+	err := run(context.Background(), []string{"-follow", "a.log", "b.log"})
+
+	assert.EqualError(t, err, "follow mode accepts a single log file")
+}
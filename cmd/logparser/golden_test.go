@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden reports of testdata/golden with the current output")
+
+// corpus lists the logs of the golden tests, with the flags they are parsed
+// with. The reports of each one are kept in testdata/golden/<name>.json.
+// testdata/corpus/captured holds parts of logs written by real servers, cut
+// like rotated logs are, while testdata/corpus/synthetic holds small logs
+// written by hand in the format of the servers no capture is available of.
+var corpus = []struct {
+	name string
+	path string
+	args []string
+}{
+	{name: "qgames", path: "../../assets/qgames.log"},
+	{name: "test", path: "../../assets/test.log"},
+	{name: "qgames_restart", path: "testdata/corpus/captured/qgames_restart.log"},
+	{name: "qgames_rotated", path: "testdata/corpus/captured/qgames_rotated.log"},
+	{name: "baseq3_tdm", path: "testdata/corpus/synthetic/baseq3_tdm.log", args: []string{"-means", "testdata/means/baseq3.json"}},
+	{name: "baseq3_ctf", path: "testdata/corpus/synthetic/baseq3_ctf.log"},
+	{name: "quakelive", path: "testdata/corpus/synthetic/quakelive.log", args: []string{"-means", "testdata/means/quakelive.json"}},
+	{name: "openarena", path: "testdata/corpus/synthetic/openarena.log"},
+}
+
+func TestGolden(t *testing.T) {
+	modes := []struct {
+		name string
+		args []string
+	}{
+		{name: "Sequential"},
+		{name: "Concurrent", args: []string{"-match-workers", "4"}},
+	}
+
+	for _, tc := range corpus {
+		for _, mode := range modes {
+			if *update && mode.args != nil {
+				continue
+			}
+
+			t.Run(tc.name+" "+mode.name, func(t *testing.T) {
+				content, err := os.ReadFile(tc.path)
+				assert.NoError(t, err)
+				// the report is written next to the log
+				log := filepath.Join(t.TempDir(), filepath.Base(tc.path))
+				assert.NoError(t, os.WriteFile(log, content, 0o644))
+
+				args := append(append(append([]string{}, tc.args...), mode.args...), log)
+				assert.NoError(t, run(context.Background(), args))
+
+				actual, err := os.ReadFile(log + ".json")
+				assert.NoError(t, err)
+
+				golden := filepath.Join("testdata", "golden", tc.name+".json")
+				if *update {
+					assert.NoError(t, os.WriteFile(golden, actual, 0o644))
+					return
+				}
+
+				expected, err := os.ReadFile(golden)
+				assert.NoError(t, err, "run go test ./cmd/logparser -run TestGolden -update to create it")
+				assert.Equal(t, string(expected), string(actual), "the report of %s changed, run go test ./cmd/logparser -run TestGolden -update if it is expected", tc.path)
+			})
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/vhrboliveira/quake-log-parser-test/internal/storage"
+)
+
+func ingest(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("ingest", flag.ContinueOnError)
+	dbPath := flags.String("db", "quake.db", "path of the SQLite database")
+	parser := parserFlags(flags)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	options, err := parser()
+	if err != nil {
+		return err
+	}
+
+	paths, err := logPaths(flags.Args())
+	if err != nil {
+		return err
+	}
+
+	store, err := storage.Open(*dbPath)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	// SQLite has a single writer, so the logs are ingested one at a time
+	for _, path := range paths {
+		changed, err := store.Ingest(ctx, path, options...)
+		if err != nil {
+			return fmt.Errorf("error processing the log file: %w", err)
+		}
+
+		if changed {
+			fmt.Println("quake log file ingested:", path)
+		} else {
+			fmt.Println("quake log file unchanged since the last ingestion:", path)
+		}
+	}
+
+	return nil
+}
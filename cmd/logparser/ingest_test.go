@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIngest(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "quake.db")
+
+	for range 2 {
+		assert.NoError(t, run(context.Background(), []string{"ingest", "-db", dbPath, "../../assets/test.log"}))
+	}
+
+	db, err := sql.Open("sqlite3", dbPath)
+	assert.NoError(t, err)
+	defer db.Close()
+
+	var matches int
+	assert.NoError(t, db.QueryRow("SELECT COUNT(*) FROM matches").Scan(&matches))
+	assert.Equal(t, 3, matches)
+}
+
+func TestIngestNonExistentLogFile(t *testing.T) {
+	err := run(context.Background(), []string{"ingest", "-db", filepath.Join(t.TempDir(), "quake.db"), "../../assets/nonexistent.log"})
+
+	assert.ErrorContains(t, err, "error processing the log file: failed to open quake log file:")
+}
+
+func TestIngestParserFlags(t *testing.T) {
+	tmpdir := t.TempDir()
+	dbPath := filepath.Join(tmpdir, "quake.db")
+	logPath := filepath.Join(tmpdir, "games.log")
+	log := "  0:00 InitGame: \\mapname\\q3dm17\n" +
+		"  0:01 ClientUserinfoChanged: 2 n\\Player1\\t\\0\n" +
+		"  0:01 ClientUserinfoChanged: 3 n\\Player2\\t\\0\n" +
+		"  0:02 Kill: 2 3 32: Player1 killed Player2 by\n" +
+		"  0:03 ShutdownGame:\n"
+	assert.NoError(t, os.WriteFile(logPath, []byte(log), 0o644))
+
+	err := run(context.Background(), []string{"ingest", "-db", dbPath, "-means", "testdata/means/quakelive.json", logPath})
+	assert.NoError(t, err)
+
+	db, err := sql.Open("sqlite3", dbPath)
+	assert.NoError(t, err)
+	defer db.Close()
+
+	var method string
+	assert.NoError(t, db.QueryRow("SELECT method FROM kills").Scan(&method))
+	assert.Equal(t, "MOD_HMG", method, "The means of death of the server are used")
+
+	err = run(context.Background(), []string{"ingest", "-db", dbPath, "-orphans", "keep", logPath})
+	assert.EqualError(t, err, `unknown orphan policy "keep", expected drop, buffer or attach`)
+}
+
+func TestIngestScoring(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		rules    string
+		expected int
+	}{
+		{name: "Default rules", expected: 3},
+		{name: "Kill points", args: []string{"-kill-points", "2"}, expected: 8},
+		{name: "Scoring file", rules: `{"world_death_points": 0, "weapon_points": {"MOD_ROCKET_SPLASH": 3}}`, expected: 12},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tmpdir := t.TempDir()
+			dbPath := filepath.Join(tmpdir, "quake.db")
+			args := append([]string{"ingest", "-db", dbPath}, tc.args...)
+			if tc.rules != "" {
+				rulesFile := filepath.Join(tmpdir, "rules.json")
+				assert.NoError(t, os.WriteFile(rulesFile, []byte(tc.rules), 0o644))
+				args = append(args, "-scoring", rulesFile)
+			}
+
+			assert.NoError(t, run(context.Background(), append(args, "../../assets/test.log")))
+
+			db, err := sql.Open("sqlite3", dbPath)
+			assert.NoError(t, err)
+			defer db.Close()
+
+			var kills int
+			assert.NoError(t, db.QueryRow(`SELECT p.kills FROM match_players p JOIN log_matches l ON l.match_id = p.match_id
+				WHERE l.game = 'game_2' AND p.name = 'Isgalamido'`).Scan(&kills))
+			assert.Equal(t, tc.expected, kills)
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/vhrboliveira/quake-log-parser-test/internal/batch"
+	"github.com/vhrboliveira/quake-log-parser-test/internal/file"
+	"github.com/vhrboliveira/quake-log-parser-test/internal/live"
+	"github.com/vhrboliveira/quake-log-parser-test/pkg/quakelog"
+)
+
+func liveFeed(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("live", flag.ContinueOnError)
+	addr := flags.String("addr", ":8080", "address the live feed listens on")
+	parser := parserFlags(flags)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	options, err := parser()
+	if err != nil {
+		return err
+	}
+
+	paths, err := logPaths(flags.Args())
+	if err != nil {
+		return err
+	}
+	if len(paths) != 1 {
+		return errors.New("live mode accepts a single log file")
+	}
+
+	hub := live.NewHub()
+	srv := &http.Server{
+		Addr:              *addr,
+		Handler:           hub.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	srv.RegisterOnShutdown(hub.Close)
+
+	return followAndServe(ctx, paths[0], hub, srv, options...)
+}
+
+// followAndServe tails the log at path publishing its events to hub while srv
+// serves them, until ctx is done or the log can no longer be read.
+func followAndServe(ctx context.Context, path string, hub *live.Hub, srv *http.Server, options ...quakelog.Option) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	result := make(chan error, 1)
+	go func() {
+		// the pipeline only returns before ctx is done when it fails
+		result <- batch.Pipeline{
+			Read:  []file.ReadOption{file.WithFollow()},
+			Parse: append(options, quakelog.OnEvent(hub.Publish)),
+		}.Run(ctx, path)
+		cancel()
+	}()
+
+	if err := listenUntil(ctx, srv); err != nil {
+		return err
+	}
+
+	if err := <-result; err != nil {
+		return fmt.Errorf("error processing the log file: %w", err)
+	}
+
+	return nil
+}
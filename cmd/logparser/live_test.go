@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vhrboliveira/quake-log-parser-test/internal/live"
+	"github.com/vhrboliveira/quake-log-parser-test/pkg/quakelog"
+)
+
+func TestFollowAndServe(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "games.log")
+	assert.NoError(t, os.WriteFile(path, []byte("0:00 InitGame: \\mapname\\q3dm17\n"+
+		"0:01 ClientUserinfoChanged: 2 n\\Player1\\t\\0\n"+
+		"0:02 Kill: 1022 2 22: <world> killed Player1 by MOD_TRIGGER_HURT\n"), 0o644))
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	addr := listener.Addr().String()
+	assert.NoError(t, listener.Close())
+
+	hub := live.NewHub()
+	srv := &http.Server{Addr: addr, Handler: hub.Handler()}
+	srv.RegisterOnShutdown(hub.Close)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	result := make(chan error, 1)
+	go func() {
+		result <- followAndServe(ctx, path, hub, srv)
+	}()
+
+	var scoreboard quakelog.Scoreboard
+	assert.Eventually(t, func() bool {
+		resp, err := http.Get("http://" + addr + "/scoreboard")
+		if err != nil {
+			return false
+		}
+		defer resp.Body.Close()
+		return resp.StatusCode == http.StatusOK && json.NewDecoder(resp.Body).Decode(&scoreboard) == nil &&
+			scoreboard.TotalKills == 1
+	}, 5*time.Second, 20*time.Millisecond)
+
+	assert.Equal(t, []quakelog.ScoreboardEntry{{ID: 2, Name: "Player1", Kills: -1}}, scoreboard.Players)
+
+	cancel()
+
+	select {
+	case err := <-result:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("live mode did not stop")
+	}
+}
+
+func TestFollowAndServeNonExistentLogFile(t *testing.T) {
+	hub := live.NewHub()
+	srv := &http.Server{Addr: "127.0.0.1:0", Handler: hub.Handler()}
+
+	err := followAndServe(context.Background(), "../../assets/nonexistent.log", hub, srv)
+
+	assert.ErrorContains(t, err, "error processing the log file: failed to open quake log file:")
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/vhrboliveira/quake-log-parser-test/internal/loggen"
+	"github.com/vhrboliveira/quake-log-parser-test/pkg/quakelog"
+)
+
+// generateLog writes a synthetic log to out, or to the -o file, along with
+// the report the parser should give for it when -report is set.
+func generateLog(ctx context.Context, args []string, out io.Writer) error {
+	defaults := loggen.DefaultConfig()
+	flags := flag.NewFlagSet("loggen", flag.ContinueOnError)
+	seed := flags.Uint64("seed", defaults.Seed, "seed of the generator: the same flags always generate the same log")
+	matches := flags.Int("matches", defaults.Matches, "number of matches (ignored with -size)")
+	size := flags.String("size", "", "approximate size of the log, like 500M or 10G, instead of a number of matches")
+	players := flags.Int("players", defaults.Players, "maximum number of players of a match")
+	kills := flags.Int("kills", defaults.Kills, "average number of kills of a match")
+	corruption := flags.Float64("corruption", 0, "fraction of the matches damaged, between 0 and 1")
+	corrupt := flags.String("corrupt", "", "comma separated kinds of damage done (splice, shutdown, garbage, orphan), all of them by default")
+	output := flags.String("o", "", "path of the log (default standard output)")
+	report := flags.String("report", "", "path of the expected report, written like the reports of the parser")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	config := loggen.Config{
+		Seed:       *seed,
+		Matches:    *matches,
+		Players:    *players,
+		Kills:      *kills,
+		Corruption: *corruption,
+	}
+	if *size != "" {
+		bytes, err := parseSize(*size)
+		if err != nil {
+			return err
+		}
+		config.Size = bytes
+	}
+	if *corrupt != "" {
+		config.Corrupt = strings.Split(*corrupt, ",")
+	}
+	if err := config.Validate(); err != nil {
+		return err
+	}
+
+	if *output != "" {
+		logFile, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer logFile.Close()
+		out = logFile
+	}
+
+	if *report == "" {
+		return loggen.Generate(contextWriter{ctx: ctx, w: out}, config, func(map[string]quakelog.MatchReport) error { return nil })
+	}
+
+	reportFile, err := os.Create(*report)
+	if err != nil {
+		return err
+	}
+	defer reportFile.Close()
+
+	expected := &reportWriter{w: reportFile}
+	if err := loggen.Generate(contextWriter{ctx: ctx, w: out}, config, expected.write); err != nil {
+		return err
+	}
+
+	return expected.close()
+}
+
+// reportWriter writes the matches as the JSON array file.WriteReport would
+// write, one match at a time, so the expected report of a huge log is not
+// kept in memory.
+type reportWriter struct {
+	w       io.Writer
+	matches int
+}
+
+func (r *reportWriter) write(match map[string]quakelog.MatchReport) error {
+	jsonData, err := json.MarshalIndent(match, "  ", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshaling JSON: %w", err)
+	}
+
+	separator := ",\n  "
+	if r.matches == 0 {
+		separator = "[\n  "
+	}
+	r.matches++
+
+	if _, err := io.WriteString(r.w, separator+string(jsonData)); err != nil {
+		return fmt.Errorf("error writing to file: %w", err)
+	}
+
+	return nil
+}
+
+// close ends the array.
+func (r *reportWriter) close() error {
+	end := "\n]\n"
+	if r.matches == 0 {
+		end = "[]\n"
+	}
+
+	if _, err := io.WriteString(r.w, end); err != nil {
+		return fmt.Errorf("error writing to file: %w", err)
+	}
+
+	return nil
+}
+
+// parseSize converts a size in bytes, optionally ending with K, M or G, to
+// bytes.
+func parseSize(size string) (int64, error) {
+	multiplier := int64(1)
+	switch strings.ToUpper(size[len(size)-1:]) {
+	case "K":
+		multiplier = 1 << 10
+	case "M":
+		multiplier = 1 << 20
+	case "G":
+		multiplier = 1 << 30
+	}
+	if multiplier > 1 {
+		size = size[:len(size)-1]
+	}
+
+	value, err := strconv.ParseInt(size, 10, 64)
+	if err != nil || value <= 0 {
+		return 0, fmt.Errorf("invalid size %q, expected a number of bytes like 1024, 500M or 10G", size)
+	}
+	return value * multiplier, nil
+}
+
+// contextWriter stops writing to w once ctx is done, so a long generation can
+// be interrupted.
+type contextWriter struct {
+	ctx context.Context
+	w   io.Writer
+}
+
+func (cw contextWriter) Write(p []byte) (int, error) {
+	if err := cw.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return cw.w.Write(p)
+}
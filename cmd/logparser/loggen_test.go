@@ -0,0 +1,124 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vhrboliveira/quake-log-parser-test/internal/batch"
+	"github.com/vhrboliveira/quake-log-parser-test/internal/file"
+)
+
+func TestGenerateLog(t *testing.T) {
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, "games.log")
+	reportPath := filepath.Join(dir, "expected.json")
+
+	err := generateLog(context.Background(), []string{"-seed", "3", "-matches", "6", "-corruption", "0.5", "-o", logPath, "-report", reportPath}, nil)
+	assert.NoError(t, err)
+
+	expected, err := file.ReadReport(reportPath)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, expected)
+
+	report, err := batch.ParseFile(context.Background(), logPath)
+	assert.NoError(t, err)
+	// compared as JSON, like the expected report was written
+	assert.JSONEq(t, toJSON(t, expected), toJSON(t, report))
+
+	// the expected report is formatted like the reports of the parser, so
+	// they can also be compared with diff
+	written, err := os.ReadFile(reportPath)
+	assert.NoError(t, err)
+	parsedPath := filepath.Join(dir, "parsed.json")
+	assert.NoError(t, file.WriteReport(parsedPath, expected))
+	parsed, err := os.ReadFile(parsedPath)
+	assert.NoError(t, err)
+	assert.Equal(t, string(parsed), string(written))
+
+	var out bytes.Buffer
+	assert.NoError(t, diffReports(context.Background(), []string{reportPath, logPath}, &out))
+	assert.Equal(t, "No differences\n", out.String())
+}
+
+func TestGenerateLogStdout(t *testing.T) {
+	var first, second bytes.Buffer
+
+	assert.NoError(t, generateLog(context.Background(), []string{"-matches", "2"}, &first))
+	assert.NoError(t, generateLog(context.Background(), []string{"-matches", "2"}, &second))
+	assert.Contains(t, first.String(), "InitGame:")
+	assert.Equal(t, first.String(), second.String(), "The same flags generate the same log")
+}
+
+func TestGenerateLogErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		args   []string
+		errMsg string
+	}{
+		{name: "Invalid size", args: []string{"-size", "10X"}, errMsg: `invalid size "10X"`},
+		{name: "Unknown corruption", args: []string{"-corruption", "0.1", "-corrupt", "splice,flood"}, errMsg: `unknown corruption "flood"`},
+		{name: "Corruption out of range", args: []string{"-corruption", "2"}, errMsg: "corruption must be between 0 and 1, got 2"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var out bytes.Buffer
+
+			err := generateLog(context.Background(), tc.args, &out)
+
+			assert.ErrorContains(t, err, tc.errMsg)
+			assert.Empty(t, out.String())
+		})
+	}
+}
+
+func TestGenerateLogInterrupted(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var out bytes.Buffer
+
+	err := generateLog(ctx, []string{"-size", "1G"}, &out)
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Empty(t, out.String())
+}
+
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		size     string
+		expected int64
+		errMsg   string
+	}{
+		{size: "1024", expected: 1024},
+		{size: "64K", expected: 64 << 10},
+		{size: "500m", expected: 500 << 20},
+		{size: "10G", expected: 10 << 30},
+		{size: "G", errMsg: `invalid size ""`},
+		{size: "-5M", errMsg: `invalid size "-5"`},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.size, func(t *testing.T) {
+			size, err := parseSize(tc.size)
+
+			if tc.errMsg != "" {
+				assert.ErrorContains(t, err, tc.errMsg)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, size)
+		})
+	}
+}
+
+func toJSON(t *testing.T, v any) string {
+	t.Helper()
+	data, err := json.Marshal(v)
+	assert.NoError(t, err)
+	return string(data)
+}
@@ -1,41 +1,183 @@
 package main
 
 import (
+	"context"
 	"errors"
+	"flag"
 	"fmt"
 	"os"
+	"os/signal"
+	"runtime"
+	"syscall"
 
+	"github.com/vhrboliveira/quake-log-parser-test/internal/batch"
 	"github.com/vhrboliveira/quake-log-parser-test/internal/file"
-	"github.com/vhrboliveira/quake-log-parser-test/internal/logparser"
+	"github.com/vhrboliveira/quake-log-parser-test/pkg/quakelog"
 )
 
-func run() error {
-	filePath := os.Getenv("LOG_FILE")
-	if filePath == "" {
-		return errors.New("environment variable LOG_FILE is not set. Please set the LOG_FILE environment variable to the path of the quake log file. Ex: \"assets/quake.log\"")
+// run executes the command line in args. Cancelling ctx stops it, writing
+// the reports of what was parsed until then.
+func run(ctx context.Context, args []string) error {
+	if len(args) > 0 {
+		switch args[0] {
+		case "serve":
+			return serve(ctx, args[1:])
+		case "live":
+			return liveFeed(ctx, args[1:])
+		case "ingest":
+			return ingest(ctx, args[1:])
+		case "query":
+			return queryKills(ctx, args[1:], os.Stdout)
+		case "loggen":
+			return generateLog(ctx, args[1:], os.Stdout)
+		case "diff":
+			return diffReports(ctx, args[1:], os.Stdout)
+		}
 	}
 
-	lines := make(chan string)
-	gameReport := make(chan logparser.GameReport)
-	done := make(chan bool)
-	errChan := make(chan error, 1)
+	flags := flag.NewFlagSet("logparser", flag.ContinueOnError)
+	workers := flags.Int("workers", runtime.NumCPU(), "maximum number of log files processed concurrently")
+	merge := flags.Bool("merge", false, "write a single report with the matches of every log file")
+	output := flags.String("output", "merged_report.json", "path of the merged report (used with -merge)")
+	resume := flags.Bool("resume", false, "only parse the lines appended since the previous run, using <log>.checkpoint")
+	follow := flags.Bool("follow", false, "keep reading a growing log and append each finished match to <log>.jsonl")
+	matchWorkers := flags.Int("match-workers", 0, "split each log at its InitGame lines and parse its matches on this many goroutines (0 parses it sequentially)")
+	parser := parserFlags(flags)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	options, err := parser()
+	if err != nil {
+		return err
+	}
+
+	paths, err := logPaths(flags.Args())
+	if err != nil {
+		return err
+	}
+
+	if *matchWorkers > 0 && (*follow || *resume) {
+		return errors.New("-match-workers cannot be combined with -follow or -resume")
+	}
+
+	if *follow {
+		if len(paths) != 1 {
+			return errors.New("follow mode accepts a single log file")
+		}
+		return followLog(ctx, paths[0], options...)
+	}
+
+	if *merge && *resume {
+		return errors.New("resume mode cannot be combined with -merge")
+	}
+
+	parse := batch.ParseFile
+	process := batch.ProcessFile
+	if *resume {
+		process = batch.ResumeFile
+	}
+	if *matchWorkers > 0 {
+		parse = func(ctx context.Context, path string, options ...quakelog.Option) (quakelog.GameReport, error) {
+			return batch.ParseFileConcurrently(ctx, path, *matchWorkers, options...)
+		}
+		process = func(ctx context.Context, path string, options ...quakelog.Option) error {
+			report, err := parse(ctx, path, options...)
+			if err != nil {
+				return err
+			}
+			return file.WriteReport(path+".json", report)
+		}
+	}
+
+	if *merge {
+		// the fingerprints drop the matches of logs overlapping each other
+		options = append(options, quakelog.WithFingerprints())
+		err = mergeFiles(ctx, paths, *workers, *output, func(ctx context.Context, path string) (report quakelog.GameReport, err error) {
+			err = diagnosed(os.Stderr, path, options, func(options ...quakelog.Option) error {
+				report, err = parse(ctx, path, options...)
+				return err
+			})
+			return report, err
+		})
+	} else {
+		err = joinErrors(batch.Each(ctx, paths, *workers, func(_ int, path string) error {
+			return diagnosed(os.Stderr, path, options, func(options ...quakelog.Option) error {
+				return process(ctx, path, options...)
+			})
+		}))
+	}
+	if ctx.Err() != nil {
+		return errors.New("log parsing interrupted, the reports only include the matches finished until then")
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("log parsing completed successfully")
+	return nil
+}
+
+// logPaths expands the log files passed as arguments, falling back to the
+// LOG_FILE environment variable when there are none.
+func logPaths(patterns []string) ([]string, error) {
+	if len(patterns) == 0 {
+		filePath := os.Getenv("LOG_FILE")
+		if filePath == "" {
+			return nil, errors.New("environment variable LOG_FILE is not set. Please set the LOG_FILE environment variable to the path of the quake log file or pass the log files as arguments. Ex: \"assets/quake.log\"")
+		}
+		patterns = []string{filePath}
+	}
+
+	return file.ExpandPaths(patterns)
+}
 
-	go file.ReadFile(filePath, lines, errChan)
-	go logparser.ParseLines(lines, gameReport)
-	go file.WriteFile(filePath, gameReport, done, errChan)
+func mergeFiles(ctx context.Context, paths []string, workers int, output string,
+	parse func(ctx context.Context, path string) (quakelog.GameReport, error)) error {
+	reports := make([]quakelog.GameReport, len(paths))
+	errs := batch.Each(ctx, paths, workers, func(i int, path string) error {
+		report, err := parse(ctx, path)
+		reports[i] = report
+		return err
+	})
+	// once interrupted, the merged report is written with what was parsed
+	if err := joinErrors(errs); err != nil && ctx.Err() == nil {
+		return err
+	}
+
+	merged := batch.Merge(paths, reports)
+	if err := file.WriteReport(output, merged); err != nil {
+		return err
+	}
+
+	dropped := -len(merged)
+	for _, report := range reports {
+		dropped += len(report)
+	}
+	totals := merged.Totals()
+	fmt.Printf("merged %d matches with %d kills from %d log files, dropping %d duplicated matches\n",
+		totals.Matches, totals.TotalKills, len(paths), dropped)
+	return nil
+}
 
-	select {
-	case <-done:
-		fmt.Println("log parsing completed successfully")
-		return nil
-	case err := <-errChan:
-		close(errChan)
-		return fmt.Errorf("error processing the log file: %v", err)
+func joinErrors(errs []error) error {
+	failed := make([]error, 0)
+	for _, err := range errs {
+		if err != nil {
+			failed = append(failed, fmt.Errorf("error processing the log file: %w", err))
+		}
 	}
+
+	return errors.Join(failed...)
 }
 
 func main() {
-	if err := run(); err != nil {
+	// the first signal stops the running command, which still writes what it
+	// parsed so far, while a second one kills the process
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	context.AfterFunc(ctx, stop)
+
+	if err := run(ctx, os.Args[1:]); err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
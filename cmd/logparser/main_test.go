@@ -1,12 +1,17 @@
 package main
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"fmt"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
-	"github.com/vhrboliveira/quake-log-parser-test/internal/logparser"
+	"github.com/vhrboliveira/quake-log-parser-test/internal/file"
+	"github.com/vhrboliveira/quake-log-parser-test/pkg/quakelog"
 )
 
 func TestRun(t *testing.T) {
@@ -37,7 +42,7 @@ func TestRun(t *testing.T) {
 				os.Unsetenv("LOG_FILE")
 			}
 
-			err := run()
+			err := run(context.Background(), nil)
 
 			assert.Error(t, err)
 			assert.Contains(t, err.Error(), tc.errMessage)
@@ -45,40 +50,147 @@ func TestRun(t *testing.T) {
 	}
 }
 
+func TestRunMerge(t *testing.T) {
+	content, err := os.ReadFile("../../assets/test.log")
+	assert.NoError(t, err)
+	other, err := os.ReadFile("../../assets/qgames.log")
+	assert.NoError(t, err)
+
+	// server2.log overlaps the last two matches of server1.log
+	secondMatch := bytes.Index(content, []byte("10:00 InitGame:"))
+	secondMatch += bytes.Index(content[secondMatch+1:], []byte("10:00 InitGame:")) + 1
+	logs := map[string][]byte{
+		"server1.log": content,
+		"server2.log": content[secondMatch:],
+		"server3.log": other,
+	}
+	tmpdir := t.TempDir()
+	for name, content := range logs {
+		assert.NoError(t, os.WriteFile(filepath.Join(tmpdir, name), content, 0o644))
+	}
+	output := filepath.Join(tmpdir, "merged.json")
+
+	err = run(context.Background(), []string{"-merge", "-workers", "2", "-output", output, tmpdir})
+	assert.NoError(t, err)
+
+	content, err = os.ReadFile(output)
+	assert.NoError(t, err)
+
+	var report quakelog.GameReport
+	assert.NoError(t, json.Unmarshal(content, &report))
+	assert.Len(t, report, 24, "Expected the 3 games of server1.log and the 21 of server3.log")
+
+	assert.Equal(t, filepath.Join(tmpdir, "server1.log"), report[2]["game_3"].Source)
+	assert.Equal(t, filepath.Join(tmpdir, "server3.log"), report[3]["game_4"].Source)
+	assert.Equal(t, "Q3TOURNEY6_CTF", report[1]["game_2"].Map)
+	assert.NotEmpty(t, report[1]["game_2"].Fingerprint)
+}
+
+func TestRunMergeUnscoped(t *testing.T) {
+	tmpdir := t.TempDir()
+	for i, name := range []string{"server1.log", "server2.log"} {
+		log := fmt.Sprintf("  0:00 InitGame: \\mapname\\q3dm%d\n", i+1) +
+			"  0:01 ClientUserinfoChanged: 2 n\\Player1\\t\\0\n" +
+			"  0:02 ShutdownGame:\n" +
+			"  0:03 Kill: 1022 2 22: <world> killed Player1 by MOD_TRIGGER_HURT\n"
+		assert.NoError(t, os.WriteFile(filepath.Join(tmpdir, name), []byte(log), 0o644))
+	}
+	output := filepath.Join(tmpdir, "merged.json")
+
+	err := run(context.Background(), []string{"-merge", "-orphans", "buffer", "-output", output, tmpdir})
+	assert.NoError(t, err)
+
+	report, err := file.ReadReport(output)
+	assert.NoError(t, err)
+	names := make([]string, 0, len(report))
+	for _, game := range report {
+		for name := range game {
+			names = append(names, name)
+		}
+	}
+	assert.Equal(t, []string{"game_1", quakelog.UNSCOPED, "game_2", quakelog.UNSCOPED}, names, "The unscoped events are not numbered as a match")
+	assert.Equal(t, filepath.Join(tmpdir, "server2.log"), report[3][quakelog.UNSCOPED].Source)
+}
+
+func TestRunInterrupted(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := run(ctx, []string{"../../assets/test.log"})
+
+	assert.EqualError(t, err, "log parsing interrupted, the reports only include the matches finished until then")
+}
+
+func TestRunMatchWorkers(t *testing.T) {
+	content, err := os.ReadFile("../../assets/qgames.log")
+	assert.NoError(t, err)
+
+	tmpdir := t.TempDir()
+	sequential := filepath.Join(tmpdir, "sequential.log")
+	concurrent := filepath.Join(tmpdir, "concurrent.log")
+	assert.NoError(t, os.WriteFile(sequential, content, 0o644))
+	assert.NoError(t, os.WriteFile(concurrent, content, 0o644))
+
+	assert.NoError(t, run(context.Background(), []string{sequential}))
+	assert.NoError(t, run(context.Background(), []string{"-match-workers", "4", concurrent}))
+
+	reports := make([]quakelog.GameReport, 0, 2)
+	for _, path := range []string{sequential, concurrent} {
+		content, err := os.ReadFile(path + ".json")
+		assert.NoError(t, err)
+
+		var report quakelog.GameReport
+		assert.NoError(t, json.Unmarshal(content, &report))
+		reports = append(reports, report)
+	}
+
+	assert.Len(t, reports[0], 21)
+	assert.Equal(t, reports[0], reports[1])
+
+	err = run(context.Background(), []string{"-match-workers", "4", "-resume", sequential})
+	assert.EqualError(t, err, "-match-workers cannot be combined with -follow or -resume")
+}
+
+func TestRunResumeWithMerge(t *testing.T) {
+	err := run(context.Background(), []string{"-resume", "-merge", "../../assets/test.log"})
+
+	assert.EqualError(t, err, "resume mode cannot be combined with -merge")
+}
+
 func TestMainE2E(t *testing.T) {
 	testLogFile := "../../assets/test.log"
 	outputFile := testLogFile + ".json"
 
 	killsByMeans := map[string]int{
-		logparser.MOD_UNKNOWN:        0,
-		logparser.MOD_SHOTGUN:        0,
-		logparser.MOD_GAUNTLET:       0,
-		logparser.MOD_MACHINEGUN:     0,
-		logparser.MOD_GRENADE:        0,
-		logparser.MOD_GRENADE_SPLASH: 0,
-		logparser.MOD_ROCKET:         0,
-		logparser.MOD_ROCKET_SPLASH:  0,
-		logparser.MOD_PLASMA:         0,
-		logparser.MOD_PLASMA_SPLASH:  0,
-		logparser.MOD_RAILGUN:        0,
-		logparser.MOD_LIGHTNING:      0,
-		logparser.MOD_BFG:            0,
-		logparser.MOD_BFG_SPLASH:     0,
-		logparser.MOD_WATER:          0,
-		logparser.MOD_SLIME:          0,
-		logparser.MOD_LAVA:           0,
-		logparser.MOD_CRUSH:          0,
-		logparser.MOD_TELEFRAG:       0,
-		logparser.MOD_FALLING:        0,
-		logparser.MOD_SUICIDE:        0,
-		logparser.MOD_TARGET_LASER:   0,
-		logparser.MOD_TRIGGER_HURT:   0,
-		logparser.MOD_NAIL:           0,
-		logparser.MOD_CHAINGUN:       0,
-		logparser.MOD_PROXIMITY_MINE: 0,
-		logparser.MOD_KAMIKAZE:       0,
-		logparser.MOD_JUICED:         0,
-		logparser.MOD_GRAPPLE:        0,
+		quakelog.MOD_UNKNOWN:        0,
+		quakelog.MOD_SHOTGUN:        0,
+		quakelog.MOD_GAUNTLET:       0,
+		quakelog.MOD_MACHINEGUN:     0,
+		quakelog.MOD_GRENADE:        0,
+		quakelog.MOD_GRENADE_SPLASH: 0,
+		quakelog.MOD_ROCKET:         0,
+		quakelog.MOD_ROCKET_SPLASH:  0,
+		quakelog.MOD_PLASMA:         0,
+		quakelog.MOD_PLASMA_SPLASH:  0,
+		quakelog.MOD_RAILGUN:        0,
+		quakelog.MOD_LIGHTNING:      0,
+		quakelog.MOD_BFG:            0,
+		quakelog.MOD_BFG_SPLASH:     0,
+		quakelog.MOD_WATER:          0,
+		quakelog.MOD_SLIME:          0,
+		quakelog.MOD_LAVA:           0,
+		quakelog.MOD_CRUSH:          0,
+		quakelog.MOD_TELEFRAG:       0,
+		quakelog.MOD_FALLING:        0,
+		quakelog.MOD_SUICIDE:        0,
+		quakelog.MOD_TARGET_LASER:   0,
+		quakelog.MOD_TRIGGER_HURT:   0,
+		quakelog.MOD_NAIL:           0,
+		quakelog.MOD_CHAINGUN:       0,
+		quakelog.MOD_PROXIMITY_MINE: 0,
+		quakelog.MOD_KAMIKAZE:       0,
+		quakelog.MOD_JUICED:         0,
+		quakelog.MOD_GRAPPLE:        0,
 	}
 
 	if _, err := os.Stat(outputFile); err == nil {
@@ -90,12 +202,12 @@ func TestMainE2E(t *testing.T) {
 	os.Setenv("LOG_FILE", testLogFile)
 	defer os.Unsetenv("LOG_FILE")
 
-	run()
+	run(context.Background(), nil)
 
 	content, err := os.ReadFile(outputFile)
 	assert.NoError(t, err)
 
-	var report logparser.GameReport
+	var report quakelog.GameReport
 	err = json.Unmarshal(content, &report)
 	assert.NoError(t, err)
 
@@ -104,6 +216,7 @@ func TestMainE2E(t *testing.T) {
 	// Game 1 verification
 	game1 := report[0]["game_1"]
 	assert.NotNil(t, game1)
+	assert.Equal(t, "Q3TOURNEY6_CTF", game1.Map)
 	assert.Equal(t, 0, game1.TotalKills)
 	assert.Len(t, game1.Players, 1)
 	assert.Equal(t, game1.KillsByMeans, killsByMeans)
@@ -115,9 +228,9 @@ func TestMainE2E(t *testing.T) {
 	// Game 2 verification
 	game2 := report[1]["game_2"]
 	killsByMeansGame2 := killsByMeans
-	killsByMeansGame2[logparser.MOD_ROCKET] = 1
-	killsByMeansGame2[logparser.MOD_ROCKET_SPLASH] = 8
-	killsByMeansGame2[logparser.MOD_TRIGGER_HURT] = 1
+	killsByMeansGame2[quakelog.MOD_ROCKET] = 1
+	killsByMeansGame2[quakelog.MOD_ROCKET_SPLASH] = 8
+	killsByMeansGame2[quakelog.MOD_TRIGGER_HURT] = 1
 	assert.NotNil(t, game2)
 	assert.Equal(t, 10, game2.TotalKills)
 	assert.Len(t, game2.Players, 4)
@@ -136,9 +249,9 @@ func TestMainE2E(t *testing.T) {
 	// Game 3 verification
 	game3 := report[2]["game_3"]
 	killsByMeansGame3 := killsByMeans
-	killsByMeansGame3[logparser.MOD_ROCKET] = 3
-	killsByMeansGame3[logparser.MOD_ROCKET_SPLASH] = 6
-	killsByMeansGame3[logparser.MOD_TRIGGER_HURT] = 2
+	killsByMeansGame3[quakelog.MOD_ROCKET] = 3
+	killsByMeansGame3[quakelog.MOD_ROCKET_SPLASH] = 6
+	killsByMeansGame3[quakelog.MOD_TRIGGER_HURT] = 2
 	assert.NotNil(t, game3)
 	assert.Equal(t, 11, game3.TotalKills)
 	assert.Len(t, game3.Players, 4)
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/vhrboliveira/quake-log-parser-test/pkg/quakelog"
+)
+
+// meansFlags registers the flag loading the means of death of mods and other
+// engines. The returned function builds the parser option once the flags are
+// parsed, keeping the built-in means of death when the flag is not set.
+func meansFlags(flags *flag.FlagSet) func() (quakelog.Option, error) {
+	path := flags.String("means", "", "JSON file with the means of death of the server (see README)")
+
+	return func() (quakelog.Option, error) {
+		if *path == "" {
+			return quakelog.WithMeansRegistry(quakelog.DefaultMeansRegistry()), nil
+		}
+
+		f, err := os.Open(*path)
+		if err != nil {
+			return nil, fmt.Errorf("error reading means of death: %w", err)
+		}
+		defer f.Close()
+
+		registry, err := quakelog.ReadMeansRegistry(f)
+		if err != nil {
+			return nil, err
+		}
+
+		return quakelog.WithMeansRegistry(registry), nil
+	}
+}
+
+// parserFlags registers the flags configuring the parser, returning a
+// function that builds its options once the flags are parsed.
+func parserFlags(flags *flag.FlagSet) func() ([]quakelog.Option, error) {
+	scoring := scoringFlags(flags)
+	means := meansFlags(flags)
+	strict := flags.Bool("strict", false, "fail on the first unknown or malformed log line instead of skipping it")
+	diagnostics := flags.Bool("diagnostics", false, "add the anomalies of each match to its report, under sections.diagnostics")
+	orphans := flags.String("orphans", quakelog.ORPHAN_DROP, "how to handle the events written outside a match: drop, buffer or attach")
+	order := flags.String("order", quakelog.ORDER_ID, "order of the players of the reports: id, name or score")
+
+	return func() ([]quakelog.Option, error) {
+		rules, err := scoring()
+		if err != nil {
+			return nil, err
+		}
+
+		registry, err := means()
+		if err != nil {
+			return nil, err
+		}
+
+		switch *orphans {
+		case quakelog.ORPHAN_DROP, quakelog.ORPHAN_BUFFER, quakelog.ORPHAN_ATTACH:
+		default:
+			return nil, fmt.Errorf("unknown orphan policy %q, expected drop, buffer or attach", *orphans)
+		}
+
+		switch *order {
+		case quakelog.ORDER_ID, quakelog.ORDER_NAME, quakelog.ORDER_SCORE:
+		default:
+			return nil, fmt.Errorf("unknown player order %q, expected id, name or score", *order)
+		}
+
+		options := []quakelog.Option{rules, registry, quakelog.WithOrphanPolicy(*orphans), quakelog.WithPlayerOrder(*order)}
+		if *strict {
+			options = append(options, quakelog.Strict())
+		}
+		if *diagnostics {
+			options = append(options, quakelog.ReportDiagnostics())
+		}
+
+		return options, nil
+	}
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vhrboliveira/quake-log-parser-test/pkg/quakelog"
+)
+
+func TestMeansFlags(t *testing.T) {
+	log := "  0:00 InitGame: \\mapname\\ut4_turnpike\n" +
+		"  0:01 Kill: 2 3 12: Player1 killed Player2 by UT_MOD_KNIFE\n" +
+		"  0:02 ShutdownGame:\n"
+
+	meansFile := filepath.Join(t.TempDir(), "means.json")
+	assert.NoError(t, os.WriteFile(meansFile, []byte(`{"extends": "none", "means": [{"id": 12, "name": "UT_MOD_KNIFE"}]}`), 0o644))
+
+	tests := []struct {
+		name     string
+		args     []string
+		expected map[string]int
+		errMsg   string
+	}{
+		{
+			name:     "Means file",
+			args:     []string{"-means", meansFile},
+			expected: map[string]int{"UT_MOD_KNIFE": 1},
+		},
+		{
+			name:   "Missing means file",
+			args:   []string{"-means", "/nonexistent/means.json"},
+			errMsg: "error reading means of death",
+		},
+		{
+			name:   "Invalid means file",
+			args:   []string{"-means", "../../assets/test.log"},
+			errMsg: "error parsing means of death",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			flags := flag.NewFlagSet("test", flag.ContinueOnError)
+			parser := parserFlags(flags)
+			assert.NoError(t, flags.Parse(tc.args))
+
+			options, err := parser()
+			if tc.errMsg != "" {
+				assert.ErrorContains(t, err, tc.errMsg)
+				return
+			}
+			assert.NoError(t, err)
+
+			report, err := quakelog.New(options...).Parse(strings.NewReader(log))
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, report[0]["game_1"].KillsByMeans)
+		})
+	}
+
+	t.Run("Built-in means by default", func(t *testing.T) {
+		flags := flag.NewFlagSet("test", flag.ContinueOnError)
+		parser := parserFlags(flags)
+		assert.NoError(t, flags.Parse(nil))
+
+		options, err := parser()
+		assert.NoError(t, err)
+
+		report, err := quakelog.New(options...).Parse(strings.NewReader(log))
+		assert.NoError(t, err)
+		assert.Len(t, report[0]["game_1"].KillsByMeans, 30)
+	})
+}
+
+func TestOrphansFlag(t *testing.T) {
+	log := "  0:00 InitGame: \\mapname\\q3dm17\n" +
+		"  0:01 ClientUserinfoChanged: 2 n\\Player1\\t\\0\n" +
+		"  0:02 ShutdownGame:\n" +
+		"  0:03 Kill: 1022 2 22: <world> killed Player1 by MOD_TRIGGER_HURT\n"
+
+	tests := []struct {
+		name     string
+		args     []string
+		expected []string
+		errMsg   string
+	}{
+		{
+			name:     "Dropped by default",
+			expected: []string{"game_1"},
+		},
+		{
+			name:     "Buffered",
+			args:     []string{"-orphans", "buffer"},
+			expected: []string{"game_1", quakelog.UNSCOPED},
+		},
+		{
+			name:   "Unknown policy",
+			args:   []string{"-orphans", "keep"},
+			errMsg: `unknown orphan policy "keep", expected drop, buffer or attach`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			flags := flag.NewFlagSet("test", flag.ContinueOnError)
+			parser := parserFlags(flags)
+			assert.NoError(t, flags.Parse(tc.args))
+
+			options, err := parser()
+			if tc.errMsg != "" {
+				assert.EqualError(t, err, tc.errMsg)
+				return
+			}
+			assert.NoError(t, err)
+
+			report, err := quakelog.New(options...).Parse(strings.NewReader(log))
+			assert.NoError(t, err)
+
+			names := make([]string, 0)
+			for _, match := range report {
+				for name := range match {
+					names = append(names, name)
+				}
+			}
+			assert.Equal(t, tc.expected, names)
+		})
+	}
+}
+
+func TestOrderFlag(t *testing.T) {
+	log := "  0:00 InitGame: \\mapname\\q3dm17\n" +
+		"  0:01 ClientUserinfoChanged: 3 n\\Zeh\\t\\0\n" +
+		"  0:01 ClientUserinfoChanged: 2 n\\Mocinha\\t\\0\n" +
+		"  0:01 ClientUserinfoChanged: 4 n\\Isgalamido\\t\\0\n" +
+		"  0:02 Kill: 3 2 10: Zeh killed Mocinha by MOD_RAILGUN\n" +
+		"  0:03 ShutdownGame:\n"
+
+	tests := []struct {
+		name     string
+		args     []string
+		expected []string
+		errMsg   string
+	}{
+		{
+			name:     "By ID by default",
+			expected: []string{"Mocinha (ID 2)", "Zeh (ID 3)", "Isgalamido (ID 4)"},
+		},
+		{
+			name:     "By name",
+			args:     []string{"-order", "name"},
+			expected: []string{"Isgalamido (ID 4)", "Mocinha (ID 2)", "Zeh (ID 3)"},
+		},
+		{
+			name:     "By score",
+			args:     []string{"-order", "score"},
+			expected: []string{"Zeh (ID 3)", "Mocinha (ID 2)", "Isgalamido (ID 4)"},
+		},
+		{
+			name:   "Unknown order",
+			args:   []string{"-order", "kills"},
+			errMsg: `unknown player order "kills", expected id, name or score`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			flags := flag.NewFlagSet("test", flag.ContinueOnError)
+			parser := parserFlags(flags)
+			assert.NoError(t, flags.Parse(tc.args))
+
+			options, err := parser()
+			if tc.errMsg != "" {
+				assert.EqualError(t, err, tc.errMsg)
+				return
+			}
+			assert.NoError(t, err)
+
+			report, err := quakelog.New(options...).Parse(strings.NewReader(log))
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, report[0]["game_1"].Players)
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/vhrboliveira/quake-log-parser-test/internal/batch"
+	"github.com/vhrboliveira/quake-log-parser-test/internal/query"
+	"github.com/vhrboliveira/quake-log-parser-test/pkg/quakelog"
+)
+
+func queryKills(ctx context.Context, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("query", flag.ContinueOnError)
+	by := flags.String("by", "", "comma separated fields the kills are grouped by (e.g. killer,weapon)")
+	asJSON := flags.Bool("json", false, "print the result as JSON")
+	workers := flags.Int("workers", runtime.NumCPU(), "maximum number of log files parsed concurrently")
+	parser := parserFlags(flags)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	options, err := parser()
+	if err != nil {
+		return err
+	}
+
+	if flags.NArg() == 0 {
+		return errors.New("missing query expression. Ex: 'player = \"Zeh\" and weapon = MOD_RAILGUN'")
+	}
+
+	expr, err := query.Parse(flags.Arg(0))
+	if err != nil {
+		return fmt.Errorf("invalid query: %w", err)
+	}
+
+	paths, err := logPaths(flags.Args()[1:])
+	if err != nil {
+		return err
+	}
+
+	kills := make([][]query.Kill, len(paths))
+	errs := batch.Each(ctx, paths, *workers, func(i int, path string) error {
+		options := append(options[:len(options):len(options)], quakelog.OnEvent(query.Collect(path, &kills[i])))
+		_, err := batch.ParseFile(ctx, path, options...)
+		return err
+	})
+	if err := joinErrors(errs); err != nil {
+		return err
+	}
+	// counting the kills of part of the logs would be misleading
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	groupBy := make([]string, 0)
+	if *by != "" {
+		groupBy = strings.Split(*by, ",")
+	}
+
+	rows, err := query.Aggregate(concat(kills), expr, groupBy)
+	if err != nil {
+		return err
+	}
+
+	if *asJSON {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(rows)
+	}
+
+	return printRows(out, groupBy, rows)
+}
+
+func concat(kills [][]query.Kill) []query.Kill {
+	all := make([]query.Kill, 0)
+	for _, fileKills := range kills {
+		all = append(all, fileKills...)
+	}
+	return all
+}
+
+func printRows(out io.Writer, groupBy []string, rows []query.Row) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+
+	header := append(append([]string{}, groupBy...), "kills")
+	fmt.Fprintln(w, strings.ToUpper(strings.Join(header, "\t")))
+	for _, row := range rows {
+		fmt.Fprintf(w, "%s\n", strings.Join(append(append([]string{}, row.Group...), fmt.Sprint(row.Kills)), "\t"))
+	}
+
+	return w.Flush()
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vhrboliveira/quake-log-parser-test/internal/query"
+)
+
+func TestQueryKills(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		expected string
+		errMsg   string
+	}{
+		{
+			name: "Grouped table",
+			args: []string{"-by", "killer", `map = Q3TOURNEY6_CTF and weapon ~ rocket and not killer = <world>`, "../../assets/test.log"},
+			expected: "KILLER        KILLS\n" +
+				"Isgalamido    10\n" +
+				"Oootsimo      4\n" +
+				"Dono da Bola  2\n" +
+				"Chessus       1\n" +
+				"Mocinha       1\n",
+		},
+		{
+			name:     "Total",
+			args:     []string{`player = "Chessus"`, "../../assets/test.log"},
+			expected: "KILLS\n5\n",
+		},
+		{
+			name:   "Missing expression",
+			args:   []string{},
+			errMsg: "missing query expression",
+		},
+		{
+			name:   "Invalid expression",
+			args:   []string{`killer = `, "../../assets/test.log"},
+			errMsg: "invalid query: expected a value",
+		},
+		{
+			name:   "Non-existent log file",
+			args:   []string{`killer = Zeh`, "../../assets/nonexistent.log"},
+			errMsg: "error processing the log file: failed to open quake log file:",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var out bytes.Buffer
+
+			err := queryKills(context.Background(), tc.args, &out)
+
+			if tc.errMsg != "" {
+				assert.ErrorContains(t, err, tc.errMsg)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, out.String())
+		})
+	}
+}
+
+func TestQueryKillsJSON(t *testing.T) {
+	var out bytes.Buffer
+
+	err := queryKills(context.Background(), []string{"-json", "-by", "game,weapon", `killer = <world>`, "../../assets/test.log"}, &out)
+	assert.NoError(t, err)
+
+	var rows []query.Row
+	assert.NoError(t, json.Unmarshal(out.Bytes(), &rows))
+	assert.Equal(t, []query.Row{
+		{Group: []string{"game_3", "MOD_TRIGGER_HURT"}, Kills: 2},
+		{Group: []string{"game_2", "MOD_TRIGGER_HURT"}, Kills: 1},
+	}, rows)
+}
+
+func TestQueryKillsJSONStdout(t *testing.T) {
+	stdout := captureStdout(t, func() {
+		assert.NoError(t, run(context.Background(), []string{"query", "-json", `killer = <world>`, "../../assets/test.log"}))
+	})
+
+	var rows []query.Row
+	assert.NoError(t, json.Unmarshal(stdout, &rows), "only the JSON is printed to stdout")
+	assert.Equal(t, []query.Row{{Group: []string{}, Kills: 3}}, rows)
+}
+
+// captureStdout returns what fn prints to os.Stdout.
+func captureStdout(t *testing.T, fn func()) []byte {
+	t.Helper()
+
+	r, w, err := os.Pipe()
+	assert.NoError(t, err)
+
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	output := make(chan []byte)
+	go func() {
+		content, _ := io.ReadAll(r)
+		output <- content
+	}()
+
+	fn()
+	w.Close()
+
+	return <-output
+}
+
+func TestQueryKillsParserFlags(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "games.log")
+	log := "  0:00 InitGame: \\mapname\\q3dm17\n" +
+		"  0:01 ClientUserinfoChanged: 2 n\\Player1\\t\\0\n" +
+		"  0:01 ClientUserinfoChanged: 3 n\\Player2\\t\\0\n" +
+		"  0:02 Kill: 2 3 32: Player1 killed Player2 by\n" +
+		"  0:03 ShutdownGame:\n"
+	assert.NoError(t, os.WriteFile(path, []byte(log), 0o644))
+
+	tests := []struct {
+		name     string
+		args     []string
+		expected string
+		errMsg   string
+	}{
+		{
+			name:     "Default means of death",
+			args:     []string{"-by", "weapon", "killer = Player1", path},
+			expected: "WEAPON       KILLS\nMOD_UNKNOWN  1\n",
+		},
+		{
+			name:     "Means of death of the server",
+			args:     []string{"-means", "testdata/means/quakelive.json", "-by", "weapon", "killer = Player1", path},
+			expected: "WEAPON   KILLS\nMOD_HMG  1\n",
+		},
+		{
+			name:   "Strict",
+			args:   []string{"-strict", "killer = Player1", path},
+			errMsg: "line 4: missing means of death, 32 is unknown",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var out bytes.Buffer
+
+			err := queryKills(context.Background(), tc.args, &out)
+
+			if tc.errMsg != "" {
+				assert.ErrorContains(t, err, tc.errMsg)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, out.String())
+		})
+	}
+}
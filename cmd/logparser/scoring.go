@@ -0,0 +1,51 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/vhrboliveira/quake-log-parser-test/pkg/quakelog"
+)
+
+// scoringFlags registers the flags configuring the scoring rules. The
+// returned function builds the parser option once the flags are parsed:
+// the rules start from the defaults, then the -scoring file and finally the
+// flags that were set override them.
+func scoringFlags(flags *flag.FlagSet) func() (quakelog.Option, error) {
+	path := flags.String("scoring", "", "JSON file with the scoring rules (see README)")
+	killPoints := flags.Int("kill-points", 1, "points of the killer of another player")
+	suicidePoints := flags.Int("suicide-points", -1, "points of a player who killed themselves")
+	worldDeathPoints := flags.Int("world-death-points", -1, "points of a player killed by <world>")
+	teamKillPoints := flags.Int("team-kill-points", -1, "points of the killer of a teammate in team game types")
+
+	return func() (quakelog.Option, error) {
+		rules := quakelog.DefaultScoringRules()
+
+		if *path != "" {
+			content, err := os.ReadFile(*path)
+			if err != nil {
+				return nil, fmt.Errorf("error reading scoring rules: %w", err)
+			}
+			if err := json.Unmarshal(content, &rules); err != nil {
+				return nil, fmt.Errorf("error parsing scoring rules: %w", err)
+			}
+		}
+
+		flags.Visit(func(f *flag.Flag) {
+			switch f.Name {
+			case "kill-points":
+				rules.KillPoints = *killPoints
+			case "suicide-points":
+				rules.SuicidePoints = *suicidePoints
+			case "world-death-points":
+				rules.WorldDeathPoints = *worldDeathPoints
+			case "team-kill-points":
+				rules.TeamKillPoints = teamKillPoints
+			}
+		})
+
+		return quakelog.WithScoringRules(rules), nil
+	}
+}
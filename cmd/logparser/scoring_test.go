@@ -0,0 +1,77 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vhrboliveira/quake-log-parser-test/pkg/quakelog"
+)
+
+func TestScoringFlags(t *testing.T) {
+	log := "  0:00 InitGame: \\g_gametype\\4\n" +
+		"  0:01 ClientUserinfoChanged: 2 n\\Player1\\t\\1\n" +
+		"  0:01 ClientUserinfoChanged: 3 n\\Player2\\t\\1\n" +
+		"  0:02 Kill: 2 3 10: Player1 killed Player2 by MOD_RAILGUN\n" +
+		"  0:03 Kill: 3 3 7: Player2 killed Player2 by MOD_ROCKET_SPLASH\n" +
+		"  0:04 Kill: 1022 2 22: <world> killed Player1 by MOD_TRIGGER_HURT\n" +
+		"  0:05 ShutdownGame:\n"
+
+	rulesFile := filepath.Join(t.TempDir(), "rules.json")
+	assert.NoError(t, os.WriteFile(rulesFile, []byte(`{"suicide_points": 0, "team_kill_points": null, "weapon_points": {"MOD_RAILGUN": 3}}`), 0o644))
+
+	tests := []struct {
+		name     string
+		args     []string
+		expected map[string]int
+		errMsg   string
+	}{
+		{
+			name:     "Default rules",
+			args:     nil,
+			expected: map[string]int{"Player1 (ID 2)": -2, "Player2 (ID 3)": -1},
+		},
+		{
+			name:     "Rules file",
+			args:     []string{"-scoring", rulesFile},
+			expected: map[string]int{"Player1 (ID 2)": 2, "Player2 (ID 3)": 0},
+		},
+		{
+			name:     "Flags override the rules file",
+			args:     []string{"-scoring", rulesFile, "-world-death-points", "0", "-team-kill-points", "-2"},
+			expected: map[string]int{"Player1 (ID 2)": -2, "Player2 (ID 3)": 0},
+		},
+		{
+			name:   "Missing rules file",
+			args:   []string{"-scoring", "/nonexistent/rules.json"},
+			errMsg: "error reading scoring rules",
+		},
+		{
+			name:   "Invalid rules file",
+			args:   []string{"-scoring", "../../assets/test.log"},
+			errMsg: "error parsing scoring rules",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			flags := flag.NewFlagSet("test", flag.ContinueOnError)
+			scoring := scoringFlags(flags)
+			assert.NoError(t, flags.Parse(tc.args))
+
+			rules, err := scoring()
+			if tc.errMsg != "" {
+				assert.ErrorContains(t, err, tc.errMsg)
+				return
+			}
+			assert.NoError(t, err)
+
+			report, err := quakelog.New(rules).Parse(strings.NewReader(log))
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, report[0]["game_1"].Kills)
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"runtime"
+	"time"
+
+	"github.com/vhrboliveira/quake-log-parser-test/internal/server"
+)
+
+const shutdownTimeout = 5 * time.Second
+
+func serve(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("serve", flag.ContinueOnError)
+	addr := flags.String("addr", ":8080", "address the HTTP API listens on")
+	workers := flags.Int("workers", runtime.NumCPU(), "maximum number of log files parsed concurrently")
+	parser := parserFlags(flags)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	options, err := parser()
+	if err != nil {
+		return err
+	}
+
+	paths, err := logPaths(flags.Args())
+	if err != nil {
+		return err
+	}
+
+	store, err := server.Load(ctx, paths, *workers, options...)
+	if err != nil {
+		return fmt.Errorf("error processing the log file: %w", err)
+	}
+
+	srv := &http.Server{
+		Addr:              *addr,
+		Handler:           server.New(store),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	return listenUntil(ctx, srv)
+}
+
+// listenUntil serves HTTP requests until ctx is done, then gracefully shuts
+// the server down.
+func listenUntil(ctx context.Context, srv *http.Server) error {
+	errChan := make(chan error, 1)
+	go func() {
+		fmt.Println("serving the quake log API on", srv.Addr)
+		errChan <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errChan:
+		return fmt.Errorf("error serving the API: %w", err)
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), shutdownTimeout)
+	defer cancel()
+
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("error shutting down the API: %w", err)
+	}
+	if err := <-errChan; !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("error serving the API: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,170 @@
+package batch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/vhrboliveira/quake-log-parser-test/internal/checkpoint"
+	"github.com/vhrboliveira/quake-log-parser-test/internal/file"
+	"github.com/vhrboliveira/quake-log-parser-test/pkg/quakelog"
+)
+
+// Each calls fn for every path using a pool of at most workers goroutines.
+// The returned errors are indexed like paths. Once ctx is done the paths not
+// handed to fn yet are skipped, with the context error as their error.
+func Each(ctx context.Context, paths []string, workers int, fn func(i int, path string) error) []error {
+	errs := make([]error, len(paths))
+	jobs := make(chan int)
+
+	workers = max(1, min(workers, len(paths)))
+
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				errs[i] = fn(i, paths[i])
+			}
+		}()
+	}
+
+	for i := range paths {
+		if err := ctx.Err(); err != nil {
+			errs[i] = err
+			continue
+		}
+
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			errs[i] = ctx.Err()
+		}
+	}
+	close(jobs)
+
+	wg.Wait()
+
+	return errs
+}
+
+// ProcessFile parses the log at path with the given parser options and writes
+// its report next to it. Cancelling ctx stops the parsing, and the report then
+// only includes the matches finished until then.
+func ProcessFile(ctx context.Context, path string, options ...quakelog.Option) error {
+	return Pipeline{
+		Parse: options,
+		Write: func(gameReport <-chan quakelog.GameReport, errChan chan<- error) {
+			file.WriteFile(path, gameReport, errChan)
+		},
+	}.Run(ctx, path)
+}
+
+// ResumeFile parses only the lines appended to the log at path since the
+// previous run, continuing from the checkpoint stored at path + ".checkpoint"
+// and appending the new matches to the report written next to the log.
+// Cancelling ctx stops the parsing, checkpointing the lines read until then.
+// The checkpoint does not record the parser options, so they should be the
+// same on every run.
+func ResumeFile(ctx context.Context, path string, options ...quakelog.Option) error {
+	checkpointPath := path + ".checkpoint"
+	reportPath := path + ".json"
+
+	cp, err := checkpoint.Load(checkpointPath)
+	if err != nil {
+		return err
+	}
+
+	matches, err := cp.Matches(path)
+	if err != nil {
+		return err
+	}
+	if !matches {
+		fmt.Fprintln(os.Stderr, "checkpoint does not match the quake log file, parsing it from the beginning:", path)
+		cp = &checkpoint.Checkpoint{}
+	}
+
+	previous := make(quakelog.GameReport, 0)
+	if cp.Offset > 0 {
+		if previous, err = file.ReadReport(reportPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+
+	report, err := collect(ctx, path, Pipeline{
+		Read:  []file.ReadOption{file.WithOffset(cp.Offset), file.WithProgress(cp.Update)},
+		Parse: append(options, quakelog.WithState(&cp.State)),
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := file.WriteReport(reportPath, append(previous, report...)); err != nil {
+		return err
+	}
+
+	return cp.Save(checkpointPath)
+}
+
+// ParseFile parses the log at path with the given parser options and returns
+// its report. Cancelling ctx stops the parsing, and the report then only
+// includes the matches finished until then.
+func ParseFile(ctx context.Context, path string, options ...quakelog.Option) (quakelog.GameReport, error) {
+	return collect(ctx, path, Pipeline{Parse: options})
+}
+
+// ParseFileConcurrently parses the log at path like ParseFile, but parsing
+// its matches on up to workers goroutines. Cancelling ctx aborts the parsing.
+func ParseFileConcurrently(ctx context.Context, path string, workers int, options ...quakelog.Option) (quakelog.GameReport, error) {
+	fmt.Fprintln(os.Stderr, "opening quake log file:", path)
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open quake log file: %w", err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("error reading file: %w", err)
+	}
+
+	return quakelog.New(options...).ParseConcurrently(ctx, f, info.Size(), workers)
+}
+
+// collect runs p keeping the report instead of writing it.
+func collect(ctx context.Context, path string, p Pipeline) (quakelog.GameReport, error) {
+	var report quakelog.GameReport
+	p.Write = func(gameReport <-chan quakelog.GameReport, _ chan<- error) {
+		for r := range gameReport {
+			report = r
+		}
+	}
+
+	if err := p.Run(ctx, path); err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// Merge combines the reports of several files into a single one with
+// quakelog.Merge, tagging each match with the file it came from.
+func Merge(paths []string, reports []quakelog.GameReport) quakelog.GameReport {
+	sourced := make([]quakelog.GameReport, len(reports))
+
+	for i, report := range reports {
+		sourced[i] = make(quakelog.GameReport, 0, len(report))
+		for _, game := range report {
+			for gameName, match := range game {
+				match.Source = paths[i]
+				sourced[i] = append(sourced[i], map[string]quakelog.MatchReport{gameName: match})
+			}
+		}
+	}
+
+	return quakelog.Merge(sourced...)
+}
@@ -0,0 +1,248 @@
+package batch
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vhrboliveira/quake-log-parser-test/internal/file"
+	"github.com/vhrboliveira/quake-log-parser-test/pkg/quakelog"
+)
+
+func TestEach(t *testing.T) {
+	paths := []string{"a.log", "b.log", "c.log", "d.log", "e.log"}
+
+	var running, peak atomic.Int32
+	visited := make([]string, len(paths))
+
+	errs := Each(context.Background(), paths, 2, func(i int, path string) error {
+		current := running.Add(1)
+		defer running.Add(-1)
+		for {
+			old := peak.Load()
+			if current <= old || peak.CompareAndSwap(old, current) {
+				break
+			}
+		}
+
+		visited[i] = path
+		if path == "c.log" {
+			return os.ErrNotExist
+		}
+		return nil
+	})
+
+	assert.Equal(t, paths, visited)
+	assert.LessOrEqual(t, peak.Load(), int32(2))
+	assert.Equal(t, []error{nil, nil, os.ErrNotExist, nil, nil}, errs)
+}
+
+func TestEachCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	errs := Each(ctx, []string{"a.log", "b.log", "c.log"}, 1, func(_ int, _ string) error {
+		cancel()
+		return nil
+	})
+
+	assert.NoError(t, errs[0])
+	assert.ErrorIs(t, errs[2], context.Canceled, "Paths left after the cancellation are skipped")
+}
+
+func TestParseFile(t *testing.T) {
+	t.Run("Valid log file", func(t *testing.T) {
+		report, err := ParseFile(context.Background(), "../../assets/test.log")
+
+		assert.NoError(t, err)
+		assert.Len(t, report, 3)
+	})
+
+	t.Run("Non-existent file", func(t *testing.T) {
+		report, err := ParseFile(context.Background(), "/nonexistent/file.log")
+
+		assert.ErrorContains(t, err, "failed to open quake log file")
+		assert.Nil(t, report)
+	})
+}
+
+func TestParseFileConcurrently(t *testing.T) {
+	t.Run("Same report as ParseFile", func(t *testing.T) {
+		expected, err := ParseFile(context.Background(), "../../assets/qgames.log")
+		assert.NoError(t, err)
+
+		report, err := ParseFileConcurrently(context.Background(), "../../assets/qgames.log", 4)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, report)
+	})
+
+	t.Run("Non-existent file", func(t *testing.T) {
+		_, err := ParseFileConcurrently(context.Background(), "/nonexistent/file.log", 4)
+
+		assert.ErrorContains(t, err, "failed to open quake log file")
+	})
+}
+
+func TestProcessFile(t *testing.T) {
+	t.Run("Writes the report next to the log", func(t *testing.T) {
+		content, err := os.ReadFile("../../assets/test.log")
+		assert.NoError(t, err)
+
+		path := filepath.Join(t.TempDir(), "server.log")
+		assert.NoError(t, os.WriteFile(path, content, 0o644))
+
+		assert.NoError(t, ProcessFile(context.Background(), path))
+		assert.FileExists(t, path+".json")
+	})
+
+	t.Run("Non-existent file", func(t *testing.T) {
+		checkGoroutines(t)
+		path := filepath.Join(t.TempDir(), "missing.log")
+
+		err := ProcessFile(context.Background(), path)
+
+		assert.ErrorContains(t, err, "failed to open quake log file")
+	})
+
+	t.Run("Report cannot be written", func(t *testing.T) {
+		checkGoroutines(t)
+		path := filepath.Join(t.TempDir(), "server.log")
+		content, err := os.ReadFile("../../assets/qgames.log")
+		assert.NoError(t, err)
+		assert.NoError(t, os.WriteFile(path, content, 0o644))
+		assert.NoError(t, os.Mkdir(path+".json", 0o755))
+
+		err = ProcessFile(context.Background(), path)
+
+		assert.ErrorContains(t, err, "error creating file")
+	})
+
+	t.Run("Cancelled before the end", func(t *testing.T) {
+		checkGoroutines(t)
+		path := filepath.Join(t.TempDir(), "server.log")
+		content, err := os.ReadFile("../../assets/test.log")
+		assert.NoError(t, err)
+		assert.NoError(t, os.WriteFile(path, content, 0o644))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		assert.NoError(t, ProcessFile(ctx, path))
+		report, err := file.ReadReport(path + ".json")
+		assert.NoError(t, err)
+		assert.Empty(t, report, "The report is still written")
+	})
+}
+
+func TestResumeFile(t *testing.T) {
+	content, err := os.ReadFile("../../assets/test.log")
+	assert.NoError(t, err)
+
+	expected, err := ParseFile(context.Background(), "../../assets/test.log")
+	assert.NoError(t, err)
+
+	// split the log in the middle of the second match
+	split := bytes.Index(content, []byte("10:18 Kill"))
+	path := filepath.Join(t.TempDir(), "games.log")
+	assert.NoError(t, os.WriteFile(path, content[:split], 0o644))
+
+	assert.NoError(t, ResumeFile(context.Background(), path))
+	report, err := file.ReadReport(path + ".json")
+	assert.NoError(t, err)
+	assert.Len(t, report, 1, "The match in progress is kept in the checkpoint")
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+	assert.NoError(t, err)
+	_, err = f.Write(content[split:])
+	assert.NoError(t, err)
+	assert.NoError(t, f.Close())
+
+	assert.NoError(t, ResumeFile(context.Background(), path))
+	report, err = file.ReadReport(path + ".json")
+	assert.NoError(t, err)
+	assert.Equal(t, expected, report, "Resumed matches equal a full parse")
+
+	// a truncated log is parsed from the beginning
+	assert.NoError(t, os.WriteFile(path, content[:split], 0o644))
+	assert.NoError(t, ResumeFile(context.Background(), path))
+	report, err = file.ReadReport(path + ".json")
+	assert.NoError(t, err)
+	assert.Len(t, report, 1)
+	assert.Contains(t, report[0], "game_1")
+}
+
+func TestResumeFilePartialLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "games.log")
+	head := "  0:00 InitGame: \\sv_hostname\\Code Miner Server\\mapname\\q3dm17\n" +
+		"  0:01 ClientConnect: 2\n" +
+		"  1:05 Kill: 1022 2 2"
+	assert.NoError(t, os.WriteFile(path, []byte(head), 0o644))
+
+	assert.NoError(t, ResumeFile(context.Background(), path))
+
+	// the server finishes writing the line cut above
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+	assert.NoError(t, err)
+	_, err = f.WriteString("2: <world> killed Isgalamido by MOD_TRIGGER_HURT\n  1:10 ShutdownGame:\n")
+	assert.NoError(t, err)
+	assert.NoError(t, f.Close())
+
+	assert.NoError(t, ResumeFile(context.Background(), path))
+	report, err := file.ReadReport(path + ".json")
+	assert.NoError(t, err)
+	assert.Len(t, report, 1)
+	assert.Equal(t, 1, report[0]["game_1"].TotalKills, "The line cut at the end of the first run is parsed whole")
+}
+
+func TestMerge(t *testing.T) {
+	match := func(kills int) quakelog.MatchReport {
+		return quakelog.MatchReport{TotalKills: kills}
+	}
+
+	reports := []quakelog.GameReport{
+		{{"game_1": match(1)}, {"game_2": match(2)}},
+		{},
+		{{"game_1": match(3)}},
+	}
+
+	merged := Merge([]string{"a.log", "b.log", "c.log"}, reports)
+
+	assert.Equal(t, quakelog.GameReport{
+		{"game_1": quakelog.MatchReport{TotalKills: 1, Source: "a.log"}},
+		{"game_2": quakelog.MatchReport{TotalKills: 2, Source: "a.log"}},
+		{"game_3": quakelog.MatchReport{TotalKills: 3, Source: "c.log"}},
+	}, merged)
+}
+
+func TestMergeUnscoped(t *testing.T) {
+	reports := []quakelog.GameReport{
+		{{"game_1": quakelog.MatchReport{TotalKills: 1}}, {quakelog.UNSCOPED: quakelog.MatchReport{TotalKills: 2}}},
+		{{"game_1": quakelog.MatchReport{TotalKills: 3}}},
+	}
+
+	merged := Merge([]string{"a.log", "b.log"}, reports)
+
+	assert.Equal(t, quakelog.GameReport{
+		{"game_1": quakelog.MatchReport{TotalKills: 1, Source: "a.log"}},
+		{quakelog.UNSCOPED: quakelog.MatchReport{TotalKills: 2, Source: "a.log"}},
+		{"game_2": quakelog.MatchReport{TotalKills: 3, Source: "b.log"}},
+	}, merged)
+}
+
+func TestMergeOverlapping(t *testing.T) {
+	reports := []quakelog.GameReport{
+		{{"game_1": quakelog.MatchReport{TotalKills: 1, Fingerprint: "a"}}},
+		{{"game_1": quakelog.MatchReport{TotalKills: 1, Fingerprint: "a"}}, {"game_2": quakelog.MatchReport{TotalKills: 2, Fingerprint: "b"}}},
+	}
+
+	merged := Merge([]string{"full.log", "rotated.log"}, reports)
+
+	assert.Equal(t, quakelog.GameReport{
+		{"game_1": quakelog.MatchReport{TotalKills: 1, Fingerprint: "a", Source: "full.log"}},
+		{"game_2": quakelog.MatchReport{TotalKills: 2, Fingerprint: "b", Source: "rotated.log"}},
+	}, merged)
+}
@@ -0,0 +1,74 @@
+package batch
+
+import (
+	"context"
+	"sync"
+
+	"github.com/vhrboliveira/quake-log-parser-test/internal/file"
+	"github.com/vhrboliveira/quake-log-parser-test/pkg/quakelog"
+)
+
+// Pipeline reads a log file, parses its lines and hands the reports to Write,
+// each stage running in its own goroutine.
+type Pipeline struct {
+	Read  []file.ReadOption
+	Parse []quakelog.Option
+	// Write consumes the reports until gameReport is closed, sending its
+	// failure to errChan. The reports are discarded when it is nil.
+	Write func(gameReport <-chan quakelog.GameReport, errChan chan<- error)
+}
+
+// Run runs the pipeline on the log at path and returns the first error of
+// any stage, which aborts the others. Cancelling ctx only stops the reading,
+// so the matches parsed until then still reach Write. Run returns once every
+// stage has stopped.
+func (p Pipeline) Run(ctx context.Context, path string) error {
+	reading, stopReading := context.WithCancel(ctx)
+	defer stopReading()
+	parsing, abort := context.WithCancel(context.WithoutCancel(ctx))
+	defer abort()
+
+	write := p.Write
+	if write == nil {
+		write = discard
+	}
+
+	lines := make(chan string)
+	gameReport := make(chan quakelog.GameReport)
+	errChan := make(chan error)
+
+	var wg sync.WaitGroup
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		file.ReadFile(reading, path, lines, errChan, p.Read...)
+	}()
+	go func() {
+		defer wg.Done()
+		quakelog.ParseLines(parsing, lines, gameReport, errChan, p.Parse...)
+	}()
+	go func() {
+		defer wg.Done()
+		write(gameReport, errChan)
+	}()
+	go func() {
+		wg.Wait()
+		close(errChan)
+	}()
+
+	var first error
+	for err := range errChan {
+		if first == nil {
+			first = err
+			stopReading()
+			abort()
+		}
+	}
+
+	return first
+}
+
+func discard(gameReport <-chan quakelog.GameReport, _ chan<- error) {
+	for range gameReport {
+	}
+}
@@ -0,0 +1,93 @@
+package batch
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vhrboliveira/quake-log-parser-test/pkg/quakelog"
+)
+
+// checkGoroutines fails the test when goroutines started during it are still
+// running once it ends.
+func checkGoroutines(t *testing.T) {
+	t.Helper()
+
+	before := runtime.NumGoroutine()
+	t.Cleanup(func() {
+		// polled by hand, as assert.Eventually runs its own goroutines
+		deadline := time.Now().Add(time.Second)
+		for runtime.NumGoroutine() > before && time.Now().Before(deadline) {
+			time.Sleep(10 * time.Millisecond)
+		}
+		assert.LessOrEqual(t, runtime.NumGoroutine(), before, "goroutines leaked")
+	})
+}
+
+func TestPipelineRun(t *testing.T) {
+	t.Run("Reports reach Write", func(t *testing.T) {
+		checkGoroutines(t)
+		reports := make([]quakelog.GameReport, 0)
+
+		err := Pipeline{
+			Write: func(gameReport <-chan quakelog.GameReport, _ chan<- error) {
+				for report := range gameReport {
+					reports = append(reports, report)
+				}
+			},
+		}.Run(context.Background(), "../../assets/test.log")
+
+		assert.NoError(t, err)
+		assert.Len(t, reports, 1)
+		assert.Len(t, reports[0], 3)
+	})
+
+	t.Run("Nil Write discards the reports", func(t *testing.T) {
+		checkGoroutines(t)
+
+		assert.NoError(t, Pipeline{}.Run(context.Background(), "../../assets/qgames.log"))
+	})
+
+	t.Run("Failing Write aborts the other stages", func(t *testing.T) {
+		checkGoroutines(t)
+
+		err := Pipeline{
+			Write: func(_ <-chan quakelog.GameReport, errChan chan<- error) {
+				errChan <- errors.New("disk full")
+			},
+		}.Run(context.Background(), "../../assets/qgames.log")
+
+		assert.EqualError(t, err, "disk full")
+	})
+
+	t.Run("Read error", func(t *testing.T) {
+		checkGoroutines(t)
+
+		err := Pipeline{}.Run(context.Background(), "/nonexistent/file.log")
+
+		assert.ErrorContains(t, err, "failed to open quake log file")
+	})
+
+	t.Run("Cancelled context flushes the parsed matches", func(t *testing.T) {
+		checkGoroutines(t)
+		ctx, cancel := context.WithCancel(context.Background())
+		var report quakelog.GameReport
+
+		err := Pipeline{
+			// cancel as soon as the first match ends, like an interrupt would
+			Parse: []quakelog.Option{quakelog.OnMatchEnd(func(map[string]quakelog.MatchReport) {
+				cancel()
+			})},
+			Write: func(gameReport <-chan quakelog.GameReport, _ chan<- error) {
+				report = <-gameReport
+			},
+		}.Run(ctx, "../../assets/qgames.log")
+
+		assert.NoError(t, err)
+		assert.NotEmpty(t, report)
+		assert.Less(t, len(report), 21, "Reading stopped before the end of the log")
+	})
+}
@@ -0,0 +1,108 @@
+package checkpoint
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/vhrboliveira/quake-log-parser-test/pkg/quakelog"
+)
+
+// maxLineLength matches the default token size of bufio.Scanner, the longest
+// line ReadFile is able to process.
+const maxLineLength = 64 * 1024
+
+// Checkpoint records how far a log was parsed: the byte offset right after the
+// last processed line, a hash of that line to detect a replaced or truncated
+// log, and the parser state to continue a match that was still in progress.
+type Checkpoint struct {
+	Offset   int64          `json:"offset"`
+	LineHash string         `json:"line_hash"`
+	State    quakelog.State `json:"state"`
+}
+
+// Load reads the checkpoint stored at path. A missing file results in an
+// empty checkpoint, which parses the log from the beginning.
+func Load(path string) (*Checkpoint, error) {
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &Checkpoint{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading checkpoint: %w", err)
+	}
+
+	var checkpoint Checkpoint
+	if err := json.Unmarshal(content, &checkpoint); err != nil {
+		return nil, fmt.Errorf("error parsing checkpoint: %w", err)
+	}
+
+	return &checkpoint, nil
+}
+
+// Save atomically writes the checkpoint to path.
+func (c *Checkpoint) Save(path string) error {
+	jsonData, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshaling JSON: %w", err)
+	}
+
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, jsonData, 0o644); err != nil {
+		return fmt.Errorf("error writing checkpoint: %w", err)
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("error writing checkpoint: %w", err)
+	}
+
+	return nil
+}
+
+// Update records line as the last processed one, ending at offset.
+func (c *Checkpoint) Update(offset int64, line string) {
+	c.Offset = offset
+	c.LineHash = HashLine(line)
+}
+
+// Matches reports whether the line ending at the checkpoint offset in the log
+// at path is still the one that was last processed. It is false when the log
+// was truncated, rotated or replaced since the checkpoint was taken.
+func (c *Checkpoint) Matches(path string) (bool, error) {
+	if c.Offset == 0 {
+		return true, nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return false, fmt.Errorf("failed to open quake log file: %w", err)
+	}
+	defer file.Close()
+
+	start := max(0, c.Offset-maxLineLength)
+	buf := make([]byte, c.Offset-start)
+	if _, err := file.ReadAt(buf, start); err != nil {
+		if errors.Is(err, io.EOF) {
+			return false, nil
+		}
+		return false, fmt.Errorf("error reading file: %w", err)
+	}
+
+	buf = bytes.TrimSuffix(buf, []byte("\n"))
+	buf = bytes.TrimSuffix(buf, []byte("\r"))
+	if i := bytes.LastIndexByte(buf, '\n'); i >= 0 {
+		buf = buf[i+1:]
+	}
+
+	return HashLine(string(buf)) == c.LineHash, nil
+}
+
+func HashLine(line string) string {
+	sum := sha256.Sum256([]byte(line))
+	return hex.EncodeToString(sum[:])
+}
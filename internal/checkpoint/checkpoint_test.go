@@ -0,0 +1,100 @@
+package checkpoint
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vhrboliveira/quake-log-parser-test/pkg/quakelog"
+)
+
+func TestSaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "games.log.checkpoint")
+
+	cp, err := Load(path)
+	assert.NoError(t, err)
+	assert.Equal(t, &Checkpoint{}, cp)
+
+	cp.Update(42, "0:03 ShutdownGame:")
+	cp.State = quakelog.State{
+		TotalGames:  2,
+		GameStarted: true,
+		Players:     map[int]quakelog.PlayerState{2: {Name: "Player1", Kills: -1}},
+		Match:       quakelog.MatchReport{TotalKills: 1, Players: []string{}, Kills: map[string]int{}},
+	}
+	assert.NoError(t, cp.Save(path))
+
+	loaded, err := Load(path)
+	assert.NoError(t, err)
+	assert.Equal(t, cp, loaded)
+	assert.NoFileExists(t, path+".tmp")
+}
+
+func TestLoadInvalidFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "games.log.checkpoint")
+	assert.NoError(t, os.WriteFile(path, []byte("{"), 0o644))
+
+	_, err := Load(path)
+
+	assert.ErrorContains(t, err, "error parsing checkpoint")
+}
+
+func TestMatches(t *testing.T) {
+	content := "0:00 InitGame: \\sv_floodProtect\\1\r\n0:01 ClientConnect: 2\n0:02 ShutdownGame:\n"
+	firstLines := int64(len("0:00 InitGame: \\sv_floodProtect\\1\r\n0:01 ClientConnect: 2\n"))
+
+	tests := []struct {
+		name     string
+		offset   int64
+		line     string
+		expected bool
+	}{
+		{
+			name:     "Empty checkpoint",
+			offset:   0,
+			expected: true,
+		},
+		{
+			name:     "Line ending at offset",
+			offset:   firstLines,
+			line:     "0:01 ClientConnect: 2",
+			expected: true,
+		},
+		{
+			name:     "First line with CRLF",
+			offset:   int64(len("0:00 InitGame: \\sv_floodProtect\\1\r\n")),
+			line:     "0:00 InitGame: \\sv_floodProtect\\1",
+			expected: true,
+		},
+		{
+			name:     "Different line",
+			offset:   firstLines,
+			line:     "0:01 ClientConnect: 3",
+			expected: false,
+		},
+		{
+			name:     "Offset beyond the end of the file",
+			offset:   int64(len(content)) + 10,
+			line:     "0:02 ShutdownGame:",
+			expected: false,
+		},
+	}
+
+	path := filepath.Join(t.TempDir(), "games.log")
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cp := &Checkpoint{}
+			if tc.offset > 0 {
+				cp.Update(tc.offset, tc.line)
+			}
+
+			matches, err := cp.Matches(path)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, matches)
+		})
+	}
+}
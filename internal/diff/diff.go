@@ -0,0 +1,147 @@
+package diff
+
+import (
+	"sort"
+
+	"github.com/vhrboliveira/quake-log-parser-test/pkg/quakelog"
+)
+
+// Kinds of change of a match or a player.
+const (
+	ADDED   = "added"
+	REMOVED = "removed"
+	CHANGED = "changed"
+)
+
+// Delta is a value of the old report and its counterpart in the new one.
+type Delta[T comparable] struct {
+	Old T `json:"old"`
+	New T `json:"new"`
+}
+
+// Player is a player whose score changed, or who is only listed in one of
+// the reports.
+type Player struct {
+	Name   string     `json:"name"`
+	Change string     `json:"change"`
+	Kills  Delta[int] `json:"kills"`
+}
+
+// Means is a means of death whose number of kills changed.
+type Means struct {
+	Name  string     `json:"name"`
+	Kills Delta[int] `json:"kills"`
+}
+
+// Match lists the differences of a match, or its total kills when it is only
+// in one of the reports. Map and Integrity are only set when they changed.
+type Match struct {
+	Game       string         `json:"game"`
+	Change     string         `json:"change"`
+	Map        *Delta[string] `json:"map,omitempty"`
+	Integrity  *Delta[string] `json:"integrity,omitempty"`
+	TotalKills Delta[int]     `json:"total_kills"`
+	Players    []Player       `json:"players,omitempty"`
+	Means      []Means        `json:"means,omitempty"`
+}
+
+// Compare returns the matches that differ between two reports, matched by
+// name, in the order of the old report followed by the ones only in the new
+// report. Players are matched by their "name (ID n)", so a player renamed
+// between both reports is listed as removed and added.
+func Compare(old, new quakelog.GameReport) []Match {
+	newMatches := make(map[string]quakelog.MatchReport)
+	for _, game := range new {
+		for name, match := range game {
+			newMatches[name] = match
+		}
+	}
+
+	changes := make([]Match, 0)
+	seen := make(map[string]bool)
+	for _, game := range old {
+		for name, oldMatch := range game {
+			seen[name] = true
+			newMatch, ok := newMatches[name]
+			if !ok {
+				changes = append(changes, Match{Game: name, Change: REMOVED, TotalKills: Delta[int]{Old: oldMatch.TotalKills}})
+				continue
+			}
+			if change, changed := compareMatch(name, oldMatch, newMatch); changed {
+				changes = append(changes, change)
+			}
+		}
+	}
+	for _, game := range new {
+		for name, newMatch := range game {
+			if !seen[name] {
+				changes = append(changes, Match{Game: name, Change: ADDED, TotalKills: Delta[int]{New: newMatch.TotalKills}})
+			}
+		}
+	}
+
+	return changes
+}
+
+func compareMatch(name string, old, new quakelog.MatchReport) (Match, bool) {
+	change := Match{
+		Game:       name,
+		Change:     CHANGED,
+		TotalKills: Delta[int]{Old: old.TotalKills, New: new.TotalKills},
+		Players:    comparePlayers(old, new),
+		Means:      compareMeans(old.KillsByMeans, new.KillsByMeans),
+	}
+	if old.Map != new.Map {
+		change.Map = &Delta[string]{Old: old.Map, New: new.Map}
+	}
+	if old.IntegrityStatus() != new.IntegrityStatus() {
+		change.Integrity = &Delta[string]{Old: old.IntegrityStatus(), New: new.IntegrityStatus()}
+	}
+
+	changed := change.Map != nil || change.Integrity != nil || old.TotalKills != new.TotalKills ||
+		len(change.Players) > 0 || len(change.Means) > 0
+	return change, changed
+}
+
+// comparePlayers returns the players of old and new whose kills differ, in
+// the order of old followed by the ones only in new.
+func comparePlayers(old, new quakelog.MatchReport) []Player {
+	players := make([]Player, 0)
+	for _, name := range old.Players {
+		newKills, ok := new.Kills[name]
+		switch {
+		case !ok:
+			players = append(players, Player{Name: name, Change: REMOVED, Kills: Delta[int]{Old: old.Kills[name]}})
+		case newKills != old.Kills[name]:
+			players = append(players, Player{Name: name, Change: CHANGED, Kills: Delta[int]{Old: old.Kills[name], New: newKills}})
+		}
+	}
+	for _, name := range new.Players {
+		if _, ok := old.Kills[name]; !ok {
+			players = append(players, Player{Name: name, Change: ADDED, Kills: Delta[int]{New: new.Kills[name]}})
+		}
+	}
+
+	return players
+}
+
+// compareMeans returns the means of death whose kills differ, sorted by name.
+// A means of death missing from a report has no kills in it.
+func compareMeans(old, new map[string]int) []Means {
+	means := make([]Means, 0)
+	for name, kills := range old {
+		if new[name] != kills {
+			means = append(means, Means{Name: name, Kills: Delta[int]{Old: kills, New: new[name]}})
+		}
+	}
+	for name, kills := range new {
+		if _, ok := old[name]; !ok && kills != 0 {
+			means = append(means, Means{Name: name, Kills: Delta[int]{New: kills}})
+		}
+	}
+
+	sort.Slice(means, func(i, j int) bool {
+		return means[i].Name < means[j].Name
+	})
+	return means
+}
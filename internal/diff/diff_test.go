@@ -0,0 +1,75 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vhrboliveira/quake-log-parser-test/pkg/quakelog"
+)
+
+func TestCompare(t *testing.T) {
+	match := quakelog.MatchReport{
+		Map:          "q3dm17",
+		TotalKills:   4,
+		Players:      []string{"Isgalamido (ID 2)", "Mocinha (ID 3)"},
+		Kills:        map[string]int{"Isgalamido (ID 2)": 3, "Mocinha (ID 3)": 0},
+		KillsByMeans: map[string]int{"MOD_RAILGUN": 3, "MOD_FALLING": 1},
+	}
+
+	tests := []struct {
+		name     string
+		old      quakelog.GameReport
+		new      quakelog.GameReport
+		expected []Match
+	}{
+		{
+			name:     "Identical",
+			old:      quakelog.GameReport{{"game_1": match}},
+			new:      quakelog.GameReport{{"game_1": match}},
+			expected: []Match{},
+		},
+		{
+			name: "Added and removed matches",
+			old:  quakelog.GameReport{{"game_1": match}},
+			new:  quakelog.GameReport{{"game_2": match}},
+			expected: []Match{
+				{Game: "game_1", Change: REMOVED, TotalKills: Delta[int]{Old: 4}},
+				{Game: "game_2", Change: ADDED, TotalKills: Delta[int]{New: 4}},
+			},
+		},
+		{
+			name: "Changed match",
+			old:  quakelog.GameReport{{"game_1": match}},
+			new: quakelog.GameReport{{"game_1": quakelog.MatchReport{
+				Map:          "q3dm6",
+				TotalKills:   5,
+				Players:      []string{"Isgalamido (ID 2)", "Zeh (ID 4)"},
+				Kills:        map[string]int{"Isgalamido (ID 2)": 2, "Zeh (ID 4)": 1},
+				KillsByMeans: map[string]int{"MOD_RAILGUN": 3, "MOD_SHOTGUN": 2},
+				Integrity:    &quakelog.Integrity{Status: quakelog.INTEGRITY_INCOMPLETE},
+			}}},
+			expected: []Match{{
+				Game:       "game_1",
+				Change:     CHANGED,
+				Map:        &Delta[string]{Old: "q3dm17", New: "q3dm6"},
+				Integrity:  &Delta[string]{Old: quakelog.INTEGRITY_OK, New: quakelog.INTEGRITY_INCOMPLETE},
+				TotalKills: Delta[int]{Old: 4, New: 5},
+				Players: []Player{
+					{Name: "Isgalamido (ID 2)", Change: CHANGED, Kills: Delta[int]{Old: 3, New: 2}},
+					{Name: "Mocinha (ID 3)", Change: REMOVED, Kills: Delta[int]{Old: 0}},
+					{Name: "Zeh (ID 4)", Change: ADDED, Kills: Delta[int]{New: 1}},
+				},
+				Means: []Means{
+					{Name: "MOD_FALLING", Kills: Delta[int]{Old: 1}},
+					{Name: "MOD_SHOTGUN", Kills: Delta[int]{New: 2}},
+				},
+			}},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, Compare(tc.old, tc.new))
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package file
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+type follower struct {
+	ctx     context.Context
+	path    string
+	file    *os.File
+	reader  *bufio.Reader
+	offset  int64
+	partial string
+	lines   chan<- string
+	opts    readOptions
+}
+
+func followFile(ctx context.Context, file *os.File, path string, lines chan<- string, opts readOptions) error {
+	f := &follower{
+		ctx:    ctx,
+		path:   path,
+		file:   file,
+		reader: bufio.NewReader(file),
+		offset: opts.offset,
+		lines:  lines,
+		opts:   opts,
+	}
+	defer func() { f.file.Close() }()
+
+	for {
+		chunk, err := f.reader.ReadString('\n')
+		f.offset += int64(len(chunk))
+		f.partial += chunk
+
+		if err == nil {
+			line := strings.TrimRight(f.partial, "\r\n")
+			if !f.send(line) {
+				return nil
+			}
+			f.partial = ""
+			if f.opts.progress != nil {
+				f.opts.progress(f.offset, line)
+			}
+			continue
+		}
+		if err != io.EOF {
+			return fmt.Errorf("error reading file: %w", err)
+		}
+
+		// only wait when the last read brought nothing new, so a rotated file
+		// is fully drained before switching to the new one
+		if chunk != "" {
+			continue
+		}
+
+		select {
+		case <-f.ctx.Done():
+			return nil
+		case <-time.After(f.opts.pollInterval):
+		}
+
+		if err := f.checkFile(); err != nil {
+			return err
+		}
+	}
+}
+
+func (f *follower) send(line string) bool {
+	select {
+	case f.lines <- line:
+		return true
+	case <-f.ctx.Done():
+		return false
+	}
+}
+
+func (f *follower) checkFile() error {
+	info, err := os.Stat(f.path)
+	if err != nil {
+		// the file was moved away and the new one is not there yet
+		return nil
+	}
+
+	current, err := f.file.Stat()
+	if err != nil {
+		return fmt.Errorf("error reading file: %w", err)
+	}
+
+	if !os.SameFile(info, current) {
+		if current.Size() > f.offset {
+			// drain what was written to the old file before the rotation
+			return nil
+		}
+
+		file, err := os.Open(f.path)
+		if err != nil {
+			return nil
+		}
+		fmt.Fprintln(os.Stderr, "quake log file rotated, reopening:", f.path)
+
+		if f.partial != "" && !f.send(f.partial) {
+			return nil
+		}
+
+		f.file.Close()
+		f.file = file
+		f.reset()
+		return nil
+	}
+
+	if info.Size() < f.offset {
+		fmt.Fprintln(os.Stderr, "quake log file truncated, reading from the beginning:", f.path)
+		if _, err := f.file.Seek(0, io.SeekStart); err != nil {
+			return fmt.Errorf("error reading file: %w", err)
+		}
+		f.reset()
+	}
+
+	return nil
+}
+
+func (f *follower) reset() {
+	f.reader.Reset(f.file)
+	f.offset = 0
+	f.partial = ""
+}
@@ -0,0 +1,100 @@
+package file
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func appendToFile(t *testing.T, path, content string) {
+	t.Helper()
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	assert.NoError(t, err)
+	_, err = f.WriteString(content)
+	assert.NoError(t, err)
+	assert.NoError(t, f.Close())
+}
+
+func receiveLine(t *testing.T, lines <-chan string) string {
+	t.Helper()
+
+	select {
+	case line := <-lines:
+		return line
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for a line")
+		return ""
+	}
+}
+
+func TestReadFileFollow(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "games.log")
+	appendToFile(t, path, "0:00 InitGame: \\sv_floodProtect\\1\n")
+
+	lines := make(chan string)
+	errChan := make(chan error, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	finished := make(chan struct{})
+
+	go func() {
+		ReadFile(ctx, path, lines, errChan, WithFollow(), WithPollInterval(10*time.Millisecond))
+		close(finished)
+	}()
+
+	assert.Equal(t, "0:00 InitGame: \\sv_floodProtect\\1", receiveLine(t, lines))
+
+	// a line is only sent once it is complete
+	appendToFile(t, path, "0:01 ClientConnect: 2")
+	time.Sleep(50 * time.Millisecond)
+	appendToFile(t, path, "\r\n")
+	assert.Equal(t, "0:01 ClientConnect: 2", receiveLine(t, lines))
+
+	// truncation restarts from the beginning
+	assert.NoError(t, os.Truncate(path, 0))
+	time.Sleep(50 * time.Millisecond)
+	appendToFile(t, path, "0:02 ShutdownGame:\n")
+	assert.Equal(t, "0:02 ShutdownGame:", receiveLine(t, lines))
+
+	// rotation drains the old file and switches to the new one
+	appendToFile(t, path, "0:03 Exit: Timelimit hit.\n")
+	assert.NoError(t, os.Rename(path, path+".1"))
+	appendToFile(t, path+".1", "0:04 ShutdownGame:\n")
+	assert.Equal(t, "0:03 Exit: Timelimit hit.", receiveLine(t, lines))
+	assert.Equal(t, "0:04 ShutdownGame:", receiveLine(t, lines))
+
+	appendToFile(t, path, "0:00 InitGame: \\sv_floodProtect\\2\n")
+	assert.Equal(t, "0:00 InitGame: \\sv_floodProtect\\2", receiveLine(t, lines))
+
+	cancel()
+
+	select {
+	case <-finished:
+	case <-time.After(2 * time.Second):
+		t.Fatal("ReadFile did not stop")
+	}
+
+	_, open := <-lines
+	assert.False(t, open, "lines channel should be closed")
+	assert.Empty(t, errChan)
+}
+
+func TestReadFileFollowNonExistentFile(t *testing.T) {
+	lines := make(chan string)
+	errChan := make(chan error, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go ReadFile(ctx, "/nonexistent/file.log", lines, errChan, WithFollow())
+
+	select {
+	case err := <-errChan:
+		assert.ErrorContains(t, err, "failed to open quake log file")
+	case <-time.After(time.Second):
+		t.Fatal("Test timed out")
+	}
+}
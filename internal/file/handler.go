@@ -2,15 +2,25 @@ package file
 
 import (
 	"bufio"
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"strings"
 
-	"github.com/vhrboliveira/quake-log-parser-test/internal/logparser"
+	"github.com/vhrboliveira/quake-log-parser-test/pkg/quakelog"
 )
 
-func ReadFile(path string, lines chan<- string, errChan chan<- error) {
-	fmt.Println("opening quake log file:", path)
+// ReadFile sends the lines of the file at path to lines, closing it when the
+// file ends, reading fails or ctx is done.
+func ReadFile(ctx context.Context, path string, lines chan<- string, errChan chan<- error, options ...ReadOption) {
+	opts := readOptions{pollInterval: defaultPollInterval}
+	for _, option := range options {
+		option(&opts)
+	}
+
+	fmt.Fprintln(os.Stderr, "opening quake log file:", path)
 
 	file, err := os.Open(path)
 	if err != nil {
@@ -18,46 +28,115 @@ func ReadFile(path string, lines chan<- string, errChan chan<- error) {
 		close(lines)
 		return
 	}
-	defer file.Close()
 
-	scanner := bufio.NewScanner(file)
+	if opts.offset > 0 {
+		if _, err := file.Seek(opts.offset, io.SeekStart); err != nil {
+			errChan <- fmt.Errorf("error reading file: %w", err)
+			file.Close()
+			close(lines)
+			return
+		}
+	}
 
-	for scanner.Scan() {
-		lines <- scanner.Text()
+	if opts.follow {
+		if err := followFile(ctx, file, path, lines, opts); err != nil {
+			errChan <- err
+		}
+		close(lines)
+		return
 	}
+	defer file.Close()
 
-	if err := scanner.Err(); err != nil {
-		errChan <- fmt.Errorf("error reading file: %w", err)
+	reader := bufio.NewReader(file)
+	offset := opts.offset
+
+	for {
+		chunk, err := reader.ReadString('\n')
+		if err != nil && err != io.EOF {
+			errChan <- fmt.Errorf("error reading file: %w", err)
+			break
+		}
+
+		// a last line without its newline may still be being written, so when
+		// the progress is tracked it is left for the next read to pick up whole
+		complete := strings.HasSuffix(chunk, "\n")
+		if chunk == "" || (!complete && opts.progress != nil) {
+			break
+		}
+
+		line := strings.TrimRight(chunk, "\r\n")
+		select {
+		case lines <- line:
+		case <-ctx.Done():
+			close(lines)
+			return
+		}
+		offset += int64(len(chunk))
+		if opts.progress != nil {
+			opts.progress(offset, line)
+		}
+
+		if !complete {
+			break
+		}
 	}
 
 	close(lines)
 }
 
-func WriteFile(path string, gameReport <-chan logparser.GameReport, done chan<- bool, errChan chan<- error) {
-	fileName := path + ".json"
-	file, err := os.Create(fileName)
-	if err != nil {
-		errChan <- fmt.Errorf("error creating file: %w", err)
-		done <- false
-		return
-	}
-	defer file.Close()
+// WriteFile writes the reports received from gameReport to path + ".json"
+// until the channel is closed. The file is only created once the first report
+// arrives, so a pipeline aborted before that leaves no empty file behind.
+func WriteFile(path string, gameReport <-chan quakelog.GameReport, errChan chan<- error) {
+	var file *os.File
 
 	for event := range gameReport {
+		if file == nil {
+			var err error
+			if file, err = os.Create(path + ".json"); err != nil {
+				errChan <- fmt.Errorf("error creating file: %w", err)
+				return
+			}
+			defer file.Close()
+		}
+
 		jsonData, err := json.MarshalIndent(event, "", "  ")
 		if err != nil {
 			errChan <- fmt.Errorf("error marshaling JSON: %w", err)
-			done <- false
 			return
 		}
 
-		_, err = file.Write(jsonData)
+		_, err = file.Write(append(jsonData, '\n'))
 		if err != nil {
 			errChan <- fmt.Errorf("error writing to file: %w", err)
-			done <- false
 			return
 		}
 	}
+}
+
+func WriteReport(fileName string, report quakelog.GameReport) error {
+	jsonData, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshaling JSON: %w", err)
+	}
+
+	if err := os.WriteFile(fileName, append(jsonData, '\n'), 0o644); err != nil {
+		return fmt.Errorf("error writing to file: %w", err)
+	}
+
+	return nil
+}
+
+func ReadReport(fileName string) (quakelog.GameReport, error) {
+	content, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("error reading report: %w", err)
+	}
+
+	var report quakelog.GameReport
+	if err := json.Unmarshal(content, &report); err != nil {
+		return nil, fmt.Errorf("error parsing report: %w", err)
+	}
 
-	done <- true
+	return report, nil
 }
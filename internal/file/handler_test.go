@@ -1,6 +1,8 @@
 package file
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"os"
 	"path/filepath"
@@ -8,7 +10,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
-	"github.com/vhrboliveira/quake-log-parser-test/internal/logparser"
+	"github.com/vhrboliveira/quake-log-parser-test/pkg/quakelog"
 )
 
 func TestReadFile(t *testing.T) {
@@ -71,7 +73,7 @@ func TestReadFile(t *testing.T) {
 			done := make(chan bool)
 
 			go func() {
-				ReadFile(tmpfileName, lines, errChan)
+				ReadFile(context.Background(), tmpfileName, lines, errChan)
 				close(done)
 			}()
 
@@ -110,20 +112,20 @@ func TestReadFile(t *testing.T) {
 func TestWriteFile(t *testing.T) {
 	tests := []struct {
 		name    string
-		report  logparser.GameReport
+		report  quakelog.GameReport
 		setup   func() (string, func())
 		wantErr bool
 	}{
 		{
 			name: "Valid game report",
-			report: logparser.GameReport{
+			report: quakelog.GameReport{
 				{
-					"game_1": logparser.MatchReport{
+					"game_1": quakelog.MatchReport{
 						TotalKills: 1,
 						Players:    []string{"Player1"},
 						Kills:      map[string]int{"Player1": 1},
 						KillsByMeans: map[string]int{
-							logparser.MOD_ROCKET: 1,
+							quakelog.MOD_ROCKET: 1,
 						},
 					},
 				},
@@ -139,7 +141,7 @@ func TestWriteFile(t *testing.T) {
 		},
 		{
 			name:   "Empty report",
-			report: logparser.GameReport{},
+			report: quakelog.GameReport{},
 			setup: func() (string, func()) {
 				tmpdir, err := os.MkdirTemp("", "test-*")
 				assert.NoError(t, err)
@@ -151,9 +153,9 @@ func TestWriteFile(t *testing.T) {
 		},
 		{
 			name: "Fail to create file - invalid directory",
-			report: logparser.GameReport{
+			report: quakelog.GameReport{
 				{
-					"game_1": logparser.MatchReport{
+					"game_1": quakelog.MatchReport{
 						TotalKills: 1,
 					},
 				},
@@ -170,8 +172,7 @@ func TestWriteFile(t *testing.T) {
 			testFile, cleanup := tc.setup()
 			defer cleanup()
 
-			done := make(chan bool)
-			gameReport := make(chan logparser.GameReport)
+			gameReport := make(chan quakelog.GameReport)
 			errChan := make(chan error, 1)
 
 			go func() {
@@ -179,30 +180,166 @@ func TestWriteFile(t *testing.T) {
 				close(gameReport)
 			}()
 
-			go WriteFile(testFile, gameReport, done, errChan)
-
-			select {
-			case err := <-errChan:
-				if tc.wantErr {
-					assert.Error(t, err)
-				} else {
-					assert.NoError(t, err)
-				}
-			case <-done:
-				if tc.wantErr {
-					t.Error("Expected error but got none")
-				} else {
-					content, err := os.ReadFile(testFile + ".json")
-					assert.NoError(t, err)
+			WriteFile(testFile, gameReport, errChan)
 
-					var report logparser.GameReport
-					err = json.Unmarshal(content, &report)
-					assert.NoError(t, err)
-					assert.Equal(t, tc.report, report)
-				}
-			case <-time.After(time.Second):
-				t.Error("Test timed out")
+			if tc.wantErr {
+				assert.ErrorContains(t, <-errChan, "error creating file")
+				return
 			}
+			assert.Empty(t, errChan)
+
+			content, err := os.ReadFile(testFile + ".json")
+			assert.NoError(t, err)
+
+			assert.True(t, bytes.HasSuffix(content, []byte("]\n")), "the report ends with a newline")
+
+			var report quakelog.GameReport
+			err = json.Unmarshal(content, &report)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.report, report)
 		})
 	}
 }
+
+func TestWriteReport(t *testing.T) {
+	report := quakelog.GameReport{
+		{
+			"game_1": quakelog.MatchReport{
+				TotalKills:   1,
+				Players:      []string{"Player1"},
+				Kills:        map[string]int{"Player1": 1},
+				KillsByMeans: map[string]int{quakelog.MOD_ROCKET: 1},
+				Source:       "server.log",
+			},
+		},
+	}
+
+	t.Run("Valid game report", func(t *testing.T) {
+		fileName := filepath.Join(t.TempDir(), "report.json")
+
+		assert.NoError(t, WriteReport(fileName, report))
+
+		content, err := os.ReadFile(fileName)
+		assert.NoError(t, err)
+
+		assert.True(t, bytes.HasSuffix(content, []byte("]\n")), "the report ends with a newline")
+
+		var result quakelog.GameReport
+		assert.NoError(t, json.Unmarshal(content, &result))
+		assert.Equal(t, report, result)
+	})
+
+	t.Run("Fail to create file - invalid directory", func(t *testing.T) {
+		err := WriteReport("/nonexistent/directory/report.json", report)
+
+		assert.ErrorContains(t, err, "error writing to file")
+	})
+}
+
+func TestReadFileWithOffset(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "games.log")
+	content := "0:00 InitGame: \\sv_floodProtect\\1\r\n0:01 ClientConnect: 2\n0:02 ShutdownGame:\n"
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+
+	lines := make(chan string)
+	errChan := make(chan error, 1)
+	offsets := make([]int64, 0)
+
+	go ReadFile(context.Background(), path, lines, errChan,
+		WithOffset(int64(len("0:00 InitGame: \\sv_floodProtect\\1\r\n"))),
+		WithProgress(func(offset int64, _ string) {
+			offsets = append(offsets, offset)
+		}),
+	)
+
+	results := make([]string, 0)
+	for line := range lines {
+		results = append(results, line)
+	}
+
+	assert.Equal(t, []string{"0:01 ClientConnect: 2", "0:02 ShutdownGame:"}, results)
+	assert.Equal(t, []int64{int64(len(content)) - int64(len("0:02 ShutdownGame:\n")), int64(len(content))}, offsets)
+	assert.Empty(t, errChan)
+}
+
+func TestReadFilePartialLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "games.log")
+	content := "0:01 ClientConnect: 2\n0:02 Kill: 1022 2 2"
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+
+	read := func(options ...ReadOption) []string {
+		lines := make(chan string)
+		errChan := make(chan error, 1)
+		go ReadFile(context.Background(), path, lines, errChan, options...)
+
+		results := make([]string, 0)
+		for line := range lines {
+			results = append(results, line)
+		}
+		assert.Empty(t, errChan)
+		return results
+	}
+
+	assert.Equal(t, []string{"0:01 ClientConnect: 2", "0:02 Kill: 1022 2 2"}, read(),
+		"The last line is read when the progress is not tracked")
+
+	offsets := make([]int64, 0)
+	assert.Equal(t, []string{"0:01 ClientConnect: 2"}, read(WithProgress(func(offset int64, _ string) {
+		offsets = append(offsets, offset)
+	})), "The line still being written is left for the next read")
+	assert.Equal(t, []int64{int64(len("0:01 ClientConnect: 2\n"))}, offsets)
+}
+
+func TestReadFileCancel(t *testing.T) {
+	lines := make(chan string)
+	errChan := make(chan error, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	finished := make(chan struct{})
+
+	go func() {
+		ReadFile(ctx, "../../assets/qgames.log", lines, errChan)
+		close(finished)
+	}()
+
+	<-lines
+	cancel()
+
+	select {
+	case <-finished:
+	case <-time.After(time.Second):
+		t.Fatal("ReadFile did not stop")
+	}
+
+	_, open := <-lines
+	assert.False(t, open, "lines channel should be closed")
+	assert.Empty(t, errChan)
+}
+
+func TestWriteFileWithoutReport(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "games.log")
+	gameReport := make(chan quakelog.GameReport)
+	errChan := make(chan error, 1)
+	close(gameReport)
+
+	WriteFile(path, gameReport, errChan)
+
+	assert.NoFileExists(t, path+".json")
+	assert.Empty(t, errChan)
+}
+
+func TestReadReport(t *testing.T) {
+	t.Run("Non-existent file", func(t *testing.T) {
+		_, err := ReadReport("/nonexistent/report.json")
+
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+
+	t.Run("Invalid JSON", func(t *testing.T) {
+		fileName := filepath.Join(t.TempDir(), "report.json")
+		assert.NoError(t, os.WriteFile(fileName, []byte("{"), 0o644))
+
+		_, err := ReadReport(fileName)
+
+		assert.ErrorContains(t, err, "error parsing report")
+	})
+}
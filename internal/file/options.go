@@ -0,0 +1,48 @@
+package file
+
+import "time"
+
+const defaultPollInterval = 500 * time.Millisecond
+
+type ReadOption func(*readOptions)
+
+type readOptions struct {
+	follow       bool
+	pollInterval time.Duration
+	offset       int64
+	progress     func(offset int64, line string)
+}
+
+// WithFollow keeps reading the file as it grows, like `tail -F`, until the
+// context given to ReadFile is done. Truncations restart the reading from the
+// beginning of the file and rotations (the path pointing to a new file) switch
+// to the new file.
+func WithFollow() ReadOption {
+	return func(opts *readOptions) {
+		opts.follow = true
+	}
+}
+
+// WithPollInterval sets how often a followed file is checked for new data.
+func WithPollInterval(interval time.Duration) ReadOption {
+	return func(opts *readOptions) {
+		opts.pollInterval = interval
+	}
+}
+
+// WithOffset starts reading the file at the given byte offset, which must be
+// the beginning of a line.
+func WithOffset(offset int64) ReadOption {
+	return func(opts *readOptions) {
+		opts.offset = offset
+	}
+}
+
+// WithProgress registers a function called after every line is sent, with
+// the byte offset right after that line. Only lines ending in a newline are
+// sent, so a last line still being written is read whole on the next run.
+func WithProgress(progress func(offset int64, line string)) ReadOption {
+	return func(opts *readOptions) {
+		opts.progress = progress
+	}
+}
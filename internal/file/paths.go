@@ -0,0 +1,84 @@
+package file
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const logExtension = ".log"
+
+// ExpandPaths resolves every pattern (a file, a directory or a glob) into the
+// list of log files to process. Directories contribute their *.log files and
+// duplicated paths are returned only once, keeping the order they were found.
+func ExpandPaths(patterns []string) ([]string, error) {
+	paths := make([]string, 0, len(patterns))
+	seen := make(map[string]bool)
+
+	for _, pattern := range patterns {
+		matches, err := expandPattern(pattern)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, path := range matches {
+			if !seen[path] {
+				seen[path] = true
+				paths = append(paths, path)
+			}
+		}
+	}
+
+	return paths, nil
+}
+
+func expandPattern(pattern string) ([]string, error) {
+	info, err := os.Stat(pattern)
+	if err == nil && info.IsDir() {
+		return listLogFiles(pattern)
+	}
+
+	// a missing literal path is kept so ReadFile reports the open error for it
+	if err == nil || !strings.ContainsAny(pattern, "*?[") {
+		return []string{pattern}, nil
+	}
+
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+	}
+
+	paths := make([]string, 0, len(matches))
+	for _, match := range matches {
+		if info, err := os.Stat(match); err == nil && !info.IsDir() {
+			paths = append(paths, match)
+		}
+	}
+
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no files match pattern %q", pattern)
+	}
+
+	return paths, nil
+}
+
+func listLogFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read directory: %w", err)
+	}
+
+	paths := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() && filepath.Ext(entry.Name()) == logExtension {
+			paths = append(paths, filepath.Join(dir, entry.Name()))
+		}
+	}
+
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no %s files found in directory %q", logExtension, dir)
+	}
+
+	return paths, nil
+}
@@ -0,0 +1,74 @@
+package file
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpandPaths(t *testing.T) {
+	tmpdir := t.TempDir()
+	for _, name := range []string{"a.log", "b.log", "b.log.json", "c.txt"} {
+		assert.NoError(t, os.WriteFile(filepath.Join(tmpdir, name), nil, 0o644))
+	}
+	assert.NoError(t, os.Mkdir(filepath.Join(tmpdir, "empty"), 0o755))
+
+	tests := []struct {
+		name     string
+		patterns []string
+		expected []string
+		errMsg   string
+	}{
+		{
+			name:     "Single file",
+			patterns: []string{filepath.Join(tmpdir, "c.txt")},
+			expected: []string{filepath.Join(tmpdir, "c.txt")},
+		},
+		{
+			name:     "Directory lists log files only",
+			patterns: []string{tmpdir},
+			expected: []string{filepath.Join(tmpdir, "a.log"), filepath.Join(tmpdir, "b.log")},
+		},
+		{
+			name:     "Glob pattern",
+			patterns: []string{filepath.Join(tmpdir, "b.*")},
+			expected: []string{filepath.Join(tmpdir, "b.log"), filepath.Join(tmpdir, "b.log.json")},
+		},
+		{
+			name:     "Duplicates are removed",
+			patterns: []string{filepath.Join(tmpdir, "a.log"), tmpdir},
+			expected: []string{filepath.Join(tmpdir, "a.log"), filepath.Join(tmpdir, "b.log")},
+		},
+		{
+			name:     "Missing file is kept",
+			patterns: []string{"/nonexistent/file.log"},
+			expected: []string{"/nonexistent/file.log"},
+		},
+		{
+			name:     "Glob without matches",
+			patterns: []string{filepath.Join(tmpdir, "*.gz")},
+			errMsg:   "no files match pattern",
+		},
+		{
+			name:     "Directory without log files",
+			patterns: []string{filepath.Join(tmpdir, "empty")},
+			errMsg:   "no .log files found in directory",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			paths, err := ExpandPaths(tc.patterns)
+
+			if tc.errMsg != "" {
+				assert.ErrorContains(t, err, tc.errMsg)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, paths)
+		})
+	}
+}
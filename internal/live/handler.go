@@ -0,0 +1,146 @@
+package live
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	keepAliveInterval = 15 * time.Second
+	writeTimeout      = 10 * time.Second
+)
+
+var upgrader = websocket.Upgrader{
+	// the feed is public and read-only, so overlays served from any origin
+	// are allowed to connect
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// Handler exposes the live feed of the hub:
+//
+//	GET /scoreboard   the scoreboard of the current match
+//	GET /events       Server-Sent Events stream, named after the event type
+//	GET /ws           WebSocket stream, one JSON message per event
+//
+// Both streams start with the current scoreboard as a "scoreboard" event.
+func (h *Hub) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /scoreboard", h.serveScoreboard)
+	mux.HandleFunc("GET /events", h.serveEvents)
+	mux.HandleFunc("GET /ws", h.serveWebSocket)
+	return mux
+}
+
+func (h *Hub) serveScoreboard(w http.ResponseWriter, r *http.Request) {
+	scoreboard, ok := h.Scoreboard()
+	if !ok {
+		http.Error(w, "no match has started yet", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(scoreboard)
+}
+
+func (h *Hub) serveEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	messages, unsubscribe := h.Subscribe()
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	if scoreboard, ok := h.Scoreboard(); ok {
+		writeSSE(w, "scoreboard", scoreboard)
+	}
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(keepAliveInterval)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-h.done:
+			return
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+		case message := <-messages:
+			writeSSE(w, message.Event.Type, message)
+		}
+		flusher.Flush()
+	}
+}
+
+func writeSSE(w http.ResponseWriter, event string, data any) {
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		return
+	}
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, jsonData)
+}
+
+func (h *Hub) serveWebSocket(w http.ResponseWriter, r *http.Request) {
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	messages, unsubscribe := h.Subscribe()
+	defer unsubscribe()
+
+	// the clients only listen, reading is needed to notice they are gone
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	if scoreboard, ok := h.Scoreboard(); ok {
+		if err := writeWebSocket(conn, map[string]any{"scoreboard": scoreboard}); err != nil {
+			return
+		}
+	}
+
+	keepAlive := time.NewTicker(keepAliveInterval)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-closed:
+			return
+		case <-h.done:
+			conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseGoingAway, ""), time.Now().Add(writeTimeout))
+			return
+		case <-keepAlive.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(writeTimeout)); err != nil {
+				return
+			}
+		case message := <-messages:
+			if err := writeWebSocket(conn, message); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func writeWebSocket(conn *websocket.Conn, data any) error {
+	conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+	return conn.WriteJSON(data)
+}
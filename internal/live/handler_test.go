@@ -0,0 +1,127 @@
+package live
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/vhrboliveira/quake-log-parser-test/pkg/quakelog"
+)
+
+var testScoreboard = quakelog.Scoreboard{
+	Game:       "game_1",
+	Map:        "q3dm17",
+	InProgress: true,
+	TotalKills: 1,
+	Players:    []quakelog.ScoreboardEntry{{ID: 2, Name: "Isgalamido", Kills: 1}},
+}
+
+var testKill = quakelog.Event{
+	Type: quakelog.EVENT_KILL,
+	Time: "0:02",
+	Game: "game_1",
+	Kill: &quakelog.KillEvent{KillerID: 2, Killer: "Isgalamido", VictimID: 3, Victim: "Mocinha", Method: quakelog.MOD_RAILGUN},
+}
+
+// waitForSubscribers blocks until count clients are connected to the hub.
+func waitForSubscribers(t *testing.T, hub *Hub, count int) {
+	t.Helper()
+
+	assert.Eventually(t, func() bool {
+		hub.mu.Lock()
+		defer hub.mu.Unlock()
+		return len(hub.subscribers) == count
+	}, 2*time.Second, 5*time.Millisecond)
+}
+
+func TestServeScoreboard(t *testing.T) {
+	hub := NewHub()
+	handler := hub.Handler()
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/scoreboard", nil))
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+
+	hub.Publish(testKill, testScoreboard)
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/scoreboard", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.JSONEq(t, `{"game":"game_1","map":"q3dm17","in_progress":true,"total_kills":1,"players":[{"id":2,"name":"Isgalamido","kills":1}]}`, recorder.Body.String())
+}
+
+func TestServeEvents(t *testing.T) {
+	hub := NewHub()
+	hub.Publish(quakelog.Event{Type: quakelog.EVENT_MATCH_START, Game: "game_1"}, testScoreboard)
+
+	srv := httptest.NewServer(hub.Handler())
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/events")
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	reader := bufio.NewReader(resp.Body)
+	readEvent := func() (string, string) {
+		name, err := reader.ReadString('\n')
+		assert.NoError(t, err)
+		data, err := reader.ReadString('\n')
+		assert.NoError(t, err)
+		_, err = reader.ReadString('\n')
+		assert.NoError(t, err)
+		return strings.TrimPrefix(strings.TrimSpace(name), "event: "), strings.TrimPrefix(strings.TrimSpace(data), "data: ")
+	}
+
+	name, data := readEvent()
+	assert.Equal(t, "scoreboard", name)
+	var scoreboard quakelog.Scoreboard
+	assert.NoError(t, json.Unmarshal([]byte(data), &scoreboard))
+	assert.Equal(t, testScoreboard, scoreboard)
+
+	waitForSubscribers(t, hub, 1)
+	hub.Publish(testKill, testScoreboard)
+
+	name, data = readEvent()
+	assert.Equal(t, quakelog.EVENT_KILL, name)
+	var message Message
+	assert.NoError(t, json.Unmarshal([]byte(data), &message))
+	assert.Equal(t, Message{Event: testKill, Scoreboard: testScoreboard}, message)
+
+	hub.Close()
+	_, err = reader.ReadString('\n')
+	assert.Error(t, err, "The stream ends when the hub is closed")
+}
+
+func TestServeWebSocket(t *testing.T) {
+	hub := NewHub()
+	hub.Publish(quakelog.Event{Type: quakelog.EVENT_MATCH_START, Game: "game_1"}, testScoreboard)
+
+	srv := httptest.NewServer(hub.Handler())
+	defer srv.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http")+"/ws", nil)
+	assert.NoError(t, err)
+	defer conn.Close()
+
+	var first Message
+	assert.NoError(t, conn.ReadJSON(&first))
+	assert.Equal(t, testScoreboard, first.Scoreboard)
+	assert.Empty(t, first.Event.Type)
+
+	waitForSubscribers(t, hub, 1)
+	hub.Publish(testKill, testScoreboard)
+
+	var message Message
+	assert.NoError(t, conn.ReadJSON(&message))
+	assert.Equal(t, Message{Event: testKill, Scoreboard: testScoreboard}, message)
+
+	conn.Close()
+	waitForSubscribers(t, hub, 0)
+}
@@ -0,0 +1,81 @@
+package live
+
+import (
+	"sync"
+
+	"github.com/vhrboliveira/quake-log-parser-test/pkg/quakelog"
+)
+
+// subscriberBuffer is how many messages a slow client may fall behind before
+// new messages are dropped for it, so it never blocks the parser.
+const subscriberBuffer = 64
+
+type Message struct {
+	Event      quakelog.Event      `json:"event"`
+	Scoreboard quakelog.Scoreboard `json:"scoreboard"`
+}
+
+// Hub fans the events decoded by the parser out to every connected client
+// and keeps the latest scoreboard for the clients that connect later.
+type Hub struct {
+	mu          sync.Mutex
+	subscribers map[chan Message]struct{}
+	scoreboard  *quakelog.Scoreboard
+	done        chan struct{}
+	closeOnce   sync.Once
+}
+
+func NewHub() *Hub {
+	return &Hub{
+		subscribers: make(map[chan Message]struct{}),
+		done:        make(chan struct{}),
+	}
+}
+
+// Close ends every open stream, letting the HTTP server shut down.
+func (h *Hub) Close() {
+	h.closeOnce.Do(func() {
+		close(h.done)
+	})
+}
+
+// Publish has the signature of the quakelog.OnEvent handlers.
+func (h *Hub) Publish(event quakelog.Event, scoreboard quakelog.Scoreboard) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.scoreboard = &scoreboard
+	message := Message{Event: event, Scoreboard: scoreboard}
+	for subscriber := range h.subscribers {
+		select {
+		case subscriber <- message:
+		default:
+		}
+	}
+}
+
+// Subscribe returns a channel receiving every published message and the
+// function that must be called once the client is gone.
+func (h *Hub) Subscribe() (<-chan Message, func()) {
+	subscriber := make(chan Message, subscriberBuffer)
+
+	h.mu.Lock()
+	h.subscribers[subscriber] = struct{}{}
+	h.mu.Unlock()
+
+	return subscriber, func() {
+		h.mu.Lock()
+		delete(h.subscribers, subscriber)
+		h.mu.Unlock()
+	}
+}
+
+func (h *Hub) Scoreboard() (quakelog.Scoreboard, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.scoreboard == nil {
+		return quakelog.Scoreboard{}, false
+	}
+	return *h.scoreboard, true
+}
@@ -0,0 +1,47 @@
+package live
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vhrboliveira/quake-log-parser-test/pkg/quakelog"
+)
+
+func TestHub(t *testing.T) {
+	hub := NewHub()
+
+	_, ok := hub.Scoreboard()
+	assert.False(t, ok)
+
+	first, unsubscribeFirst := hub.Subscribe()
+	second, unsubscribeSecond := hub.Subscribe()
+	defer unsubscribeSecond()
+
+	event := quakelog.Event{Type: quakelog.EVENT_MATCH_START, Game: "game_1"}
+	scoreboard := quakelog.Scoreboard{Game: "game_1", InProgress: true, Players: []quakelog.ScoreboardEntry{}}
+	hub.Publish(event, scoreboard)
+
+	assert.Equal(t, Message{Event: event, Scoreboard: scoreboard}, <-first)
+	assert.Equal(t, Message{Event: event, Scoreboard: scoreboard}, <-second)
+
+	current, ok := hub.Scoreboard()
+	assert.True(t, ok)
+	assert.Equal(t, scoreboard, current)
+
+	unsubscribeFirst()
+	hub.Publish(quakelog.Event{Type: quakelog.EVENT_MATCH_END}, scoreboard)
+	assert.Empty(t, first)
+	assert.Len(t, second, 1)
+}
+
+func TestHubDropsMessagesForSlowSubscribers(t *testing.T) {
+	hub := NewHub()
+	messages, unsubscribe := hub.Subscribe()
+	defer unsubscribe()
+
+	for range subscriberBuffer + 10 {
+		hub.Publish(quakelog.Event{Type: quakelog.EVENT_KILL}, quakelog.Scoreboard{})
+	}
+
+	assert.Len(t, messages, subscriberBuffer)
+}
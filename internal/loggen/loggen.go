@@ -0,0 +1,214 @@
+package loggen
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"strings"
+
+	"github.com/vhrboliveira/quake-log-parser-test/pkg/quakelog"
+)
+
+// Kinds of damage done to the matches picked by Config.Corruption.
+const (
+	// CORRUPT_SPLICE cuts a Kill line off with the next line, losing the kill
+	CORRUPT_SPLICE = "splice"
+	// CORRUPT_SHUTDOWN ends the match without Exit, scores and ShutdownGame,
+	// like a server crash
+	CORRUPT_SHUTDOWN = "shutdown"
+	// CORRUPT_GARBAGE adds a line without the time of an entry
+	CORRUPT_GARBAGE = "garbage"
+	// CORRUPT_ORPHAN adds a kill after the ShutdownGame of the match
+	CORRUPT_ORPHAN = "orphan"
+)
+
+// Corruptions lists every kind of damage.
+var Corruptions = []string{CORRUPT_SPLICE, CORRUPT_SHUTDOWN, CORRUPT_GARBAGE, CORRUPT_ORPHAN}
+
+// Config describes the log to generate. The same Config always generates the
+// same log.
+type Config struct {
+	Seed uint64
+	// Matches is the number of matches, unless Size is set
+	Matches int
+	// Size, when positive, is the approximate size of the log in bytes:
+	// matches are added until it is reached
+	Size int64
+	// Players is the maximum number of players of a match, at least 2
+	Players int
+	// Kills is the average number of kills of a match
+	Kills int
+	// Corruption is the fraction of the matches damaged by one of Corrupt
+	Corruption float64
+	// Corrupt lists the kinds of damage done, every one of Corruptions when
+	// empty
+	Corrupt []string
+}
+
+// DefaultConfig returns a Config generating 10 intact matches of up to 8
+// players.
+func DefaultConfig() Config {
+	return Config{Seed: 1, Matches: 10, Players: 8, Kills: 40}
+}
+
+// Validate reports the settings of config no log can be generated with.
+func (config Config) Validate() error {
+	if config.Matches < 0 || config.Size < 0 || config.Kills < 0 {
+		return fmt.Errorf("matches, size and kills cannot be negative")
+	}
+	if config.Corruption < 0 || config.Corruption > 1 {
+		return fmt.Errorf("corruption must be between 0 and 1, got %g", config.Corruption)
+	}
+	for _, kind := range config.Corrupt {
+		if !isCorruption(kind) {
+			return fmt.Errorf("unknown corruption %q", kind)
+		}
+	}
+
+	return nil
+}
+
+func isCorruption(kind string) bool {
+	for _, corruption := range Corruptions {
+		if kind == corruption {
+			return true
+		}
+	}
+	return false
+}
+
+type generator struct {
+	config  Config
+	rand    *rand.Rand
+	w       *bufio.Writer
+	err     error
+	written int64
+	lines   int
+	second  int
+	games   int
+	weapons []quakelog.MeansOfDeath
+	hazards []quakelog.MeansOfDeath
+	// suicides are the means of death players kill themselves with
+	suicides []quakelog.MeansOfDeath
+	means    []quakelog.MeansOfDeath
+}
+
+// Generate writes a log following config to w, calling expected with the
+// report the parser gives for each match, with the default options, as soon
+// as the parser would finish it. A match damaged by CORRUPT_SHUTDOWN is only
+// finished by the next InitGame, so it is left out when it is the last one of
+// the log.
+func Generate(w io.Writer, config Config, expected func(map[string]quakelog.MatchReport) error) error {
+	if err := config.Validate(); err != nil {
+		return err
+	}
+	if config.Players < 2 {
+		config.Players = 2
+	}
+	if len(config.Corrupt) == 0 {
+		config.Corrupt = Corruptions
+	}
+
+	g := &generator{
+		config: config,
+		rand:   rand.New(rand.NewPCG(config.Seed, config.Seed^0x9e3779b97f4a7c15)),
+		w:      bufio.NewWriterSize(w, 1<<16),
+		means:  quakelog.DefaultMeansRegistry().Means(),
+	}
+	for _, means := range g.means {
+		switch {
+		case means.Name == quakelog.MOD_UNKNOWN || means.Name == quakelog.MOD_TELEFRAG || means.Name == quakelog.MOD_GRAPPLE:
+		case means.Category == quakelog.CATEGORY_WEAPON:
+			g.weapons = append(g.weapons, means)
+			if strings.HasSuffix(means.Name, "_SPLASH") {
+				g.suicides = append(g.suicides, means)
+			}
+		case means.Category == quakelog.CATEGORY_ENVIRONMENT:
+			g.hazards = append(g.hazards, means)
+		case means.Category == quakelog.CATEGORY_SELF:
+			g.suicides = append(g.suicides, means)
+		}
+	}
+
+	g.write(g.stamp(separator))
+	var unclosed *match
+	for i := 0; g.more(i); i++ {
+		m := g.match(g.lines)
+		if unclosed != nil {
+			// the InitGame of m finishes the match left without ShutdownGame
+			unclosed.addIssue(quakelog.Diagnostic{
+				Line:   g.lines + 1,
+				Game:   unclosed.name,
+				Time:   m.times[0],
+				Kind:   quakelog.DIAGNOSTIC_MISSING_SHUTDOWN,
+				Reason: "match ended without ShutdownGame",
+				Text:   m.lines[0],
+			})
+			if err := expected(unclosed.reported()); err != nil {
+				return err
+			}
+			unclosed = nil
+		}
+
+		for _, line := range m.lines {
+			g.write(line)
+		}
+		if g.err != nil {
+			return g.err
+		}
+
+		if !m.shutdown {
+			unclosed = m
+			continue
+		}
+		if err := expected(m.reported()); err != nil {
+			return err
+		}
+	}
+
+	if err := g.w.Flush(); err != nil {
+		return err
+	}
+	return g.err
+}
+
+// more reports whether the match i is generated.
+func (g *generator) more(i int) bool {
+	if g.config.Size > 0 {
+		return g.written < g.config.Size
+	}
+	return i < g.config.Matches
+}
+
+// write writes a line of the log.
+func (g *generator) write(line string) {
+	if g.err != nil {
+		return
+	}
+	n, err := g.w.WriteString(line + "\n")
+	g.written += int64(n)
+	g.lines++
+	g.err = err
+}
+
+// clock returns the time of the server clock, as the parser reads it.
+func (g *generator) clock() string {
+	return fmt.Sprintf("%d:%02d", g.second/60, g.second%60)
+}
+
+// stamp prefixes text with the time of the server clock, right aligned like
+// the server writes it.
+func (g *generator) stamp(text string) string {
+	return fmt.Sprintf("%6s %s", g.clock(), text)
+}
+
+// tick moves the server clock up to max seconds forward.
+func (g *generator) tick(max int) {
+	g.second += g.rand.IntN(max + 1)
+}
+
+// pick returns a random element of values.
+func pick[T any](g *generator, values []T) T {
+	return values[g.rand.IntN(len(values))]
+}
@@ -0,0 +1,133 @@
+package loggen
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vhrboliveira/quake-log-parser-test/pkg/quakelog"
+)
+
+// generate returns the log generated with config and its expected report.
+func generate(t *testing.T, config Config) (string, quakelog.GameReport) {
+	t.Helper()
+
+	var log bytes.Buffer
+	expected := make(quakelog.GameReport, 0)
+	err := Generate(&log, config, func(match map[string]quakelog.MatchReport) error {
+		expected = append(expected, match)
+		return nil
+	})
+	assert.NoError(t, err)
+
+	return log.String(), expected
+}
+
+func TestGenerate(t *testing.T) {
+	tests := []struct {
+		name   string
+		config Config
+	}{
+		{name: "Default", config: DefaultConfig()},
+		{name: "Crowded", config: Config{Seed: 7, Matches: 20, Players: 16, Kills: 100}},
+		{name: "Without kills", config: Config{Seed: 3, Matches: 5, Players: 4}},
+		{name: "Corrupted", config: Config{Seed: 11, Matches: 40, Players: 8, Kills: 30, Corruption: 0.5}},
+		{name: "Spliced", config: Config{Seed: 5, Matches: 20, Players: 6, Kills: 20, Corruption: 1, Corrupt: []string{CORRUPT_SPLICE}}},
+		{name: "Crashed", config: Config{Seed: 9, Matches: 20, Players: 6, Kills: 20, Corruption: 1, Corrupt: []string{CORRUPT_SHUTDOWN}}},
+		{name: "Garbage and orphans", config: Config{Seed: 13, Matches: 20, Players: 6, Kills: 20, Corruption: 1, Corrupt: []string{CORRUPT_GARBAGE, CORRUPT_ORPHAN}}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			log, expected := generate(t, tc.config)
+
+			report, err := quakelog.New().Parse(strings.NewReader(log))
+			assert.NoError(t, err)
+			assert.Equal(t, expected, report)
+
+			concurrent, err := quakelog.New().ParseConcurrently(context.Background(), strings.NewReader(log), int64(len(log)), 4)
+			assert.NoError(t, err)
+			assert.Equal(t, expected, concurrent)
+		})
+	}
+}
+
+func TestGenerateCorruptions(t *testing.T) {
+	tests := []struct {
+		corruption string
+		status     string
+		kind       string
+	}{
+		{corruption: CORRUPT_SPLICE, status: quakelog.INTEGRITY_CORRUPTED, kind: quakelog.DIAGNOSTIC_SPLICED_LINE},
+		{corruption: CORRUPT_SHUTDOWN, status: quakelog.INTEGRITY_INCOMPLETE, kind: quakelog.DIAGNOSTIC_MISSING_SHUTDOWN},
+		{corruption: CORRUPT_GARBAGE, status: quakelog.INTEGRITY_OK, kind: quakelog.DIAGNOSTIC_MALFORMED_LINE},
+		{corruption: CORRUPT_ORPHAN, status: quakelog.INTEGRITY_OK, kind: quakelog.DIAGNOSTIC_ORPHAN_EVENT},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.corruption, func(t *testing.T) {
+			config := Config{Seed: 1, Matches: 3, Players: 4, Kills: 10, Corruption: 1, Corrupt: []string{tc.corruption}}
+			log, expected := generate(t, config)
+
+			diagnostics := &quakelog.Diagnostics{}
+			_, err := quakelog.New(quakelog.OnDiagnostic(diagnostics.Add)).Parse(strings.NewReader(log))
+			assert.NoError(t, err)
+
+			for _, diagnostic := range diagnostics.List() {
+				assert.Equal(t, tc.kind, diagnostic.Kind)
+			}
+			assert.NotEmpty(t, diagnostics.List())
+			for _, match := range expected {
+				for _, game := range match {
+					assert.Equal(t, tc.status, game.IntegrityStatus())
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateDeterministic(t *testing.T) {
+	config := Config{Seed: 42, Matches: 5, Players: 8, Kills: 20, Corruption: 0.5}
+	first, firstReport := generate(t, config)
+	second, secondReport := generate(t, config)
+	assert.Equal(t, first, second)
+	assert.Equal(t, firstReport, secondReport)
+
+	config.Seed++
+	other, _ := generate(t, config)
+	assert.NotEqual(t, first, other)
+}
+
+func TestGenerateSize(t *testing.T) {
+	log, _ := generate(t, Config{Seed: 1, Size: 1 << 20, Players: 8, Kills: 40})
+
+	assert.GreaterOrEqual(t, len(log), 1<<20)
+	assert.Less(t, len(log), 1<<20+64<<10, "Generation stops right after the match reaching the size")
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		config Config
+		errMsg string
+	}{
+		{name: "Default", config: DefaultConfig()},
+		{name: "Negative matches", config: Config{Matches: -1}, errMsg: "matches, size and kills cannot be negative"},
+		{name: "Corruption above 1", config: Config{Corruption: 1.5}, errMsg: "corruption must be between 0 and 1, got 1.5"},
+		{name: "Unknown corruption", config: Config{Corrupt: []string{"splice", "flood"}}, errMsg: `unknown corruption "flood"`},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.config.Validate()
+
+			if tc.errMsg != "" {
+				assert.EqualError(t, err, tc.errMsg)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
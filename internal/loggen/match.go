@@ -0,0 +1,272 @@
+package loggen
+
+import (
+	"fmt"
+
+	"github.com/vhrboliveira/quake-log-parser-test/pkg/quakelog"
+)
+
+const separator = "------------------------------------------------------------"
+
+var (
+	names = []string{
+		"Isgalamido", "Dono da Bola", "Mocinha", "Zeh", "Assasinu Credi", "Oootsimo", "Mal", "Chessus",
+		"Fasano Again", "UnnamedPlayer", "Maluquinho", "Xiao", "Sarge", "Major", "Visor", "Hunter",
+	}
+	maps      = []string{"q3dm17", "q3dm6", "q3dm7", "q3dm13", "q3tourney2", "q3ctf1", "q3ctf4"}
+	models    = []string{"sarge", "xian/default", "uriel/zael", "visor", "major", "keel", "hunter"}
+	items     = []string{"weapon_rocketlauncher", "ammo_rockets", "item_armor_body", "weapon_railgun", "item_health_large", "weapon_shotgun"}
+	gameTypes = []int{0, 0, 0, quakelog.GAMETYPE_TEAM, quakelog.GAMETYPE_CTF}
+	exits     = []string{"Fraglimit hit.", "Timelimit hit.", "Capturelimit hit."}
+	// garbage are lines a crashing or misconfigured server leaves in its log
+	garbage = []string{"\x00\x00\x00\x00\x00\x00\x00\x00", "Hunk_Clear: reset the hunk ok", "----- CL_Shutdown -----"}
+)
+
+// match is a generated match: its lines, the time of each one and the report
+// the parser gives for them.
+type match struct {
+	name   string
+	lines  []string
+	times  []string
+	report quakelog.MatchReport
+	// shutdown is false for a match left without ShutdownGame
+	shutdown bool
+}
+
+type player struct {
+	ID        int
+	name      string
+	team      int
+	score     int
+	teamKills int
+}
+
+// kill is a Kill line of a match. The killer of a death caused by <world> is
+// nil.
+type kill struct {
+	line   int
+	killer *player
+	victim *player
+	means  quakelog.MeansOfDeath
+}
+
+// add appends text to the lines of m, with the time of the server clock,
+// returning its index.
+func (g *generator) add(m *match, text string) int {
+	m.lines = append(m.lines, g.stamp(text))
+	m.times = append(m.times, g.clock())
+	return len(m.lines) - 1
+}
+
+// insert adds line at the index i of the lines of m.
+func (m *match) insert(i int, line, time string) {
+	m.lines = append(m.lines[:i], append([]string{line}, m.lines[i:]...)...)
+	m.times = append(m.times[:i], append([]string{time}, m.times[i:]...)...)
+}
+
+// addIssue records an anomaly damaging m, the way the parser reports it.
+func (m *match) addIssue(issue quakelog.Diagnostic) {
+	if m.report.Integrity == nil {
+		m.report.Integrity = &quakelog.Integrity{Status: quakelog.INTEGRITY_INCOMPLETE}
+	}
+	m.report.Integrity.Issues = append(m.report.Integrity.Issues, issue)
+	if issue.Kind != quakelog.DIAGNOSTIC_MISSING_SHUTDOWN {
+		m.report.Integrity.Status = quakelog.INTEGRITY_CORRUPTED
+	}
+}
+
+func (m *match) reported() map[string]quakelog.MatchReport {
+	return map[string]quakelog.MatchReport{m.name: m.report}
+}
+
+// corruption returns the kind of damage done to the next match, if any.
+func (g *generator) corruption() string {
+	if g.config.Corruption <= 0 || g.rand.Float64() >= g.config.Corruption {
+		return ""
+	}
+	return pick(g, g.config.Corrupt)
+}
+
+// match generates the next match of the log, whose lines are written after
+// the first start lines.
+func (g *generator) match(start int) *match {
+	g.games++
+	m := &match{name: fmt.Sprintf("game_%d", g.games), shutdown: true}
+	corruption := g.corruption()
+
+	gameType, mapName := pick(g, gameTypes), pick(g, maps)
+	g.add(m, fmt.Sprintf(`InitGame: \sv_floodProtect\1\sv_hostname\Code Miner Server\g_gametype\%d\sv_maxclients\16\fraglimit\20\timelimit\15\capturelimit\8\version\ioq3 1.36 linux-x86_64 Apr 12 2009\protocol\68\mapname\%s\gamename\baseq3\g_needpass\0`, gameType, mapName))
+
+	players := make([]*player, 2+g.rand.IntN(g.config.Players-1))
+	// the players join with different names, which they may share later on
+	joined := g.rand.Perm(len(names))
+	for i := range players {
+		p := &player{ID: i + 2, name: names[joined[i%len(names)]]}
+		if gameType == quakelog.GAMETYPE_TEAM || gameType == quakelog.GAMETYPE_CTF {
+			p.team = quakelog.TEAM_RED + i%2
+		}
+		players[i] = p
+
+		g.tick(2)
+		g.add(m, fmt.Sprintf("ClientConnect: %d", p.ID))
+		g.add(m, g.userinfo(p))
+		g.add(m, fmt.Sprintf("ClientBegin: %d", p.ID))
+	}
+
+	kills := make([]kill, 0, g.config.Kills)
+	for n := g.rand.IntN(2*g.config.Kills + 1); n > 0; n-- {
+		g.tick(15)
+		switch p := pick(g, players); g.rand.IntN(20) {
+		case 0, 1, 2, 3, 4, 5:
+			g.add(m, fmt.Sprintf("Item: %d %s", p.ID, pick(g, items)))
+		case 6:
+			p.name = pick(g, names)
+			g.add(m, g.userinfo(p))
+		}
+
+		k := kill{victim: pick(g, players)}
+		switch g.rand.IntN(10) {
+		case 0, 1:
+			k.means = pick(g, g.hazards)
+		case 2:
+			k.killer, k.means = k.victim, pick(g, g.suicides)
+		default:
+			for k.killer = k.victim; k.killer == k.victim; {
+				k.killer = pick(g, players)
+			}
+			k.means = pick(g, g.weapons)
+		}
+		k.line = g.add(m, killText(k))
+		kills = append(kills, k)
+	}
+
+	lost := -1
+	if corruption == CORRUPT_SPLICE && len(kills) > 0 {
+		lost = g.rand.IntN(len(kills))
+	}
+	m.report = g.report(mapName, gameType, players, kills, lost)
+
+	shutdown := -1
+	if corruption == CORRUPT_SHUTDOWN {
+		m.shutdown = false
+	} else {
+		if g.rand.IntN(4) == 0 {
+			g.add(m, fmt.Sprintf("ClientDisconnect: %d", pick(g, players).ID))
+		}
+		g.tick(10)
+		g.add(m, "Exit: "+pick(g, exits))
+		for _, p := range players {
+			g.add(m, fmt.Sprintf("score: %d  ping: %d  client: %d %s", p.score, g.rand.IntN(200), p.ID, p.name))
+		}
+		g.tick(5)
+		shutdown = g.add(m, "ShutdownGame:")
+		g.add(m, separator)
+	}
+	// the server restarts after a crash, and now and then between matches
+	if !m.shutdown || g.second >= 60*60 || g.rand.IntN(3) == 0 {
+		g.second = 0
+		g.add(m, separator)
+	}
+
+	switch corruption {
+	case CORRUPT_SPLICE:
+		if lost >= 0 {
+			g.splice(m, start, kills[lost].line)
+		}
+	case CORRUPT_GARBAGE:
+		m.insert(1+g.rand.IntN(len(m.lines)), pick(g, garbage), "")
+	case CORRUPT_ORPHAN:
+		victim := pick(g, players)
+		hazard := pick(g, g.hazards)
+		text := fmt.Sprintf("Kill: %d %d %d: %s killed %s by %s", quakelog.WORLD_ID, victim.ID, hazard.ID, quakelog.WORLD, victim.name, hazard.Name)
+		m.insert(shutdown+1, fmt.Sprintf("%6s %s", m.times[shutdown], text), m.times[shutdown])
+	}
+
+	return m
+}
+
+// splice cuts the Kill line i of m off with the next line, as written by a
+// server stopped in the middle of the line.
+func (g *generator) splice(m *match, start, i int) {
+	prefix := len(fmt.Sprintf("%6s Kill: ", m.times[i]))
+	cut := m.lines[i][:prefix+g.rand.IntN(len(m.lines[i])-prefix)]
+	next := m.lines[i+1]
+	if next[0] != ' ' {
+		// a clock past 99 minutes is not padded, while the parser only finds
+		// entries after a space
+		cut += " "
+	}
+
+	m.lines[i] = cut + next
+	m.lines = append(m.lines[:i+1], m.lines[i+2:]...)
+	m.times = append(m.times[:i+1], m.times[i+2:]...)
+	m.addIssue(quakelog.Diagnostic{
+		Line:   start + i + 1,
+		Game:   m.name,
+		Time:   m.times[i],
+		Kind:   quakelog.DIAGNOSTIC_SPLICED_LINE,
+		Reason: "line cut off by the next entry",
+		Text:   m.lines[i],
+	})
+}
+
+// report scores kills with the default scoring rules, except the lost one,
+// and returns the report of the match.
+func (g *generator) report(mapName string, gameType int, players []*player, kills []kill, lost int) quakelog.MatchReport {
+	report := quakelog.MatchReport{
+		Map:          mapName,
+		Players:      make([]string, 0, len(players)),
+		Kills:        make(map[string]int, len(players)),
+		KillsByMeans: make(map[string]int, len(g.means)),
+	}
+	for _, means := range g.means {
+		report.KillsByMeans[means.Name] = 0
+	}
+
+	teamGame := gameType == quakelog.GAMETYPE_TEAM || gameType == quakelog.GAMETYPE_CTF
+	for i, k := range kills {
+		if i == lost {
+			continue
+		}
+		report.TotalKills++
+		report.KillsByMeans[k.means.Name]++
+
+		switch {
+		case k.killer == nil, k.killer == k.victim:
+			k.victim.score--
+		case teamGame && k.killer.team == k.victim.team:
+			k.killer.score--
+			k.killer.teamKills++
+		default:
+			k.killer.score++
+		}
+	}
+
+	teamKills := make(map[string]int)
+	for _, p := range players {
+		name := fmt.Sprintf("%s (ID %d)", p.name, p.ID)
+		report.Players = append(report.Players, name)
+		report.Kills[name] = p.score
+		if p.teamKills > 0 {
+			teamKills[name] = p.teamKills
+		}
+	}
+	if teamGame && len(teamKills) > 0 {
+		report.Sections = map[string]any{quakelog.SECTION_TEAM_KILLS: teamKills}
+	}
+
+	return report
+}
+
+func (g *generator) userinfo(p *player) string {
+	model := pick(g, models)
+	return fmt.Sprintf(`ClientUserinfoChanged: %d n\%s\t\%d\model\%s\hmodel\%s\g_redteam\\g_blueteam\\c1\4\c2\5\hc\100\w\0\l\0\tt\0\tl\0`, p.ID, p.name, p.team, model, model)
+}
+
+func killText(k kill) string {
+	killerID, killer := quakelog.WORLD_ID, quakelog.WORLD
+	if k.killer != nil {
+		killerID, killer = k.killer.ID, k.killer.name
+	}
+	return fmt.Sprintf("Kill: %d %d %d: %s killed %s by %s", killerID, k.victim.ID, k.means.ID, killer, k.victim.name, k.means.Name)
+}
@@ -0,0 +1,135 @@
+package query
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/vhrboliveira/quake-log-parser-test/pkg/quakelog"
+)
+
+// Kill is a kill event with the match it happened in.
+type Kill struct {
+	Source string `json:"source"`
+	Game   string `json:"game"`
+	Map    string `json:"map"`
+	Time   string `json:"time"`
+	quakelog.KillEvent
+}
+
+// fields returns the values of each field of a kill that expressions can
+// filter on. player is both the killer and the victim.
+var fields = map[string]func(Kill) []string{
+	"source":    func(k Kill) []string { return []string{k.Source} },
+	"game":      func(k Kill) []string { return []string{k.Game} },
+	"map":       func(k Kill) []string { return []string{k.Map} },
+	"time":      func(k Kill) []string { return []string{k.Time} },
+	"killer":    func(k Kill) []string { return []string{k.Killer} },
+	"killer_id": func(k Kill) []string { return []string{strconv.Itoa(k.KillerID)} },
+	"victim":    func(k Kill) []string { return []string{k.Victim} },
+	"victim_id": func(k Kill) []string { return []string{strconv.Itoa(k.VictimID)} },
+	"weapon":    func(k Kill) []string { return []string{k.Method} },
+	"player":    func(k Kill) []string { return []string{k.Killer, k.Victim} },
+}
+
+var fieldAliases = map[string]string{
+	"method": "weapon",
+	"mod":    "weapon",
+}
+
+func fieldName(name string) (string, bool) {
+	name = strings.ToLower(name)
+	if alias, ok := fieldAliases[name]; ok {
+		name = alias
+	}
+
+	_, ok := fields[name]
+	return name, ok
+}
+
+func FieldNames() []string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+type Row struct {
+	Group []string `json:"group"`
+	Kills int      `json:"kills"`
+}
+
+// Aggregate counts the kills matching expr grouped by the values of the by
+// fields, sorted by count, highest first. Without group fields a single row
+// with the total is returned.
+func Aggregate(kills []Kill, expr Expr, by []string) ([]Row, error) {
+	groupFields := make([]string, 0, len(by))
+	for _, field := range by {
+		name, ok := fieldName(field)
+		if !ok {
+			return nil, fmt.Errorf("unknown field %q, expected one of: %s", field, strings.Join(FieldNames(), ", "))
+		}
+		if name == "player" {
+			return nil, fmt.Errorf("cannot group by player, use killer or victim instead")
+		}
+		groupFields = append(groupFields, name)
+	}
+
+	counts := make(map[string]*Row)
+	rows := make([]*Row, 0)
+	for _, kill := range kills {
+		if !expr.Match(kill) {
+			continue
+		}
+
+		group := make([]string, len(groupFields))
+		for i, field := range groupFields {
+			group[i] = fields[field](kill)[0]
+		}
+
+		key := strings.Join(group, "\x00")
+		if _, ok := counts[key]; !ok {
+			counts[key] = &Row{Group: group}
+			rows = append(rows, counts[key])
+		}
+		counts[key].Kills++
+	}
+
+	result := make([]Row, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, *row)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Kills != result[j].Kills {
+			return result[i].Kills > result[j].Kills
+		}
+		return strings.Join(result[i].Group, "\x00") < strings.Join(result[j].Group, "\x00")
+	})
+
+	if len(groupFields) == 0 && len(result) == 0 {
+		result = append(result, Row{Group: []string{}})
+	}
+
+	return result, nil
+}
+
+// Collect returns a function handling the events decoded by the parser that
+// appends every kill to kills, tagged with its match and source.
+func Collect(source string, kills *[]Kill) func(quakelog.Event, quakelog.Scoreboard) {
+	return func(event quakelog.Event, scoreboard quakelog.Scoreboard) {
+		if event.Type != quakelog.EVENT_KILL {
+			return
+		}
+
+		*kills = append(*kills, Kill{
+			Source:    source,
+			Game:      event.Game,
+			Map:       scoreboard.Map,
+			Time:      event.Time,
+			KillEvent: *event.Kill,
+		})
+	}
+}
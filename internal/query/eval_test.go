@@ -0,0 +1,96 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vhrboliveira/quake-log-parser-test/pkg/quakelog"
+)
+
+func TestAggregate(t *testing.T) {
+	kills := []Kill{
+		testKill("Zeh", "Mocinha", quakelog.MOD_RAILGUN, "q3dm17"),
+		testKill("Zeh", "Mocinha", quakelog.MOD_RAILGUN, "q3dm17"),
+		testKill("Mocinha", "Zeh", quakelog.MOD_RAILGUN, "q3dm17"),
+		testKill("Zeh", "Isgalamido", quakelog.MOD_ROCKET, "q3dm6"),
+	}
+
+	tests := []struct {
+		name     string
+		input    string
+		by       []string
+		expected []Row
+		errMsg   string
+	}{
+		{
+			name:     "Total",
+			input:    `weapon = MOD_RAILGUN`,
+			expected: []Row{{Group: []string{}, Kills: 3}},
+		},
+		{
+			name:     "Total without matches",
+			input:    `weapon = MOD_BFG`,
+			expected: []Row{{Group: []string{}, Kills: 0}},
+		},
+		{
+			name:     "Grouped by killer",
+			input:    `map = q3dm17`,
+			by:       []string{"killer"},
+			expected: []Row{{Group: []string{"Zeh"}, Kills: 2}, {Group: []string{"Mocinha"}, Kills: 1}},
+		},
+		{
+			name:  "Grouped by several fields with ties sorted by group",
+			input: ``,
+			by:    []string{"map", "MOD"},
+			expected: []Row{
+				{Group: []string{"q3dm17", quakelog.MOD_RAILGUN}, Kills: 3},
+				{Group: []string{"q3dm6", quakelog.MOD_ROCKET}, Kills: 1},
+			},
+		},
+		{
+			name:   "Unknown group field",
+			by:     []string{"team"},
+			errMsg: `unknown field "team"`,
+		},
+		{
+			name:   "Group by player",
+			by:     []string{"player"},
+			errMsg: "cannot group by player",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			expr, err := Parse(tc.input)
+			assert.NoError(t, err)
+
+			rows, err := Aggregate(kills, expr, tc.by)
+
+			if tc.errMsg != "" {
+				assert.ErrorContains(t, err, tc.errMsg)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, rows)
+		})
+	}
+}
+
+func TestCollect(t *testing.T) {
+	kills := make([]Kill, 0)
+	collect := Collect("games.log", &kills)
+
+	scoreboard := quakelog.Scoreboard{Map: "q3dm17"}
+	killEvent := &quakelog.KillEvent{KillerID: 2, Killer: "Zeh", VictimID: 3, Victim: "Mocinha", Method: quakelog.MOD_RAILGUN}
+	collect(quakelog.Event{Type: quakelog.EVENT_MATCH_START, Game: "game_1"}, scoreboard)
+	collect(quakelog.Event{Type: quakelog.EVENT_KILL, Time: "1:00", Game: "game_1", Kill: killEvent}, scoreboard)
+
+	assert.Equal(t, []Kill{testKill("Zeh", "Mocinha", quakelog.MOD_RAILGUN, "q3dm17")}, kills)
+}
+
+func TestFieldNames(t *testing.T) {
+	assert.Equal(t, []string{
+		"game", "killer", "killer_id", "map", "player", "source", "time", "victim", "victim_id", "weapon",
+	}, FieldNames())
+}
@@ -0,0 +1,127 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenString
+	tokenOperator
+	tokenLParen
+	tokenRParen
+	tokenAnd
+	tokenOr
+	tokenNot
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+	pos   int
+}
+
+func (t token) String() string {
+	if t.kind == tokenEOF {
+		return "end of expression"
+	}
+	return fmt.Sprintf("%q", t.value)
+}
+
+// tokenize splits an expression into tokens. Identifiers are words made of
+// letters, digits, underscores, dots, colons, dashes and angle brackets, so
+// unquoted values like MOD_RAILGUN, q3dm17, 12:30 or <world> are accepted;
+// anything else, like names with spaces, must be double quoted.
+func tokenize(input string) ([]token, error) {
+	tokens := make([]token, 0)
+	runes := []rune(input)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+
+		case r == '(':
+			tokens = append(tokens, token{kind: tokenLParen, value: "(", pos: i})
+			i++
+
+		case r == ')':
+			tokens = append(tokens, token{kind: tokenRParen, value: ")", pos: i})
+			i++
+
+		case r == '=' || r == '~':
+			tokens = append(tokens, token{kind: tokenOperator, value: string(r), pos: i})
+			i++
+
+		case r == '!':
+			if i+1 >= len(runes) || runes[i+1] != '=' {
+				return nil, fmt.Errorf("unexpected character %q at position %d", r, i)
+			}
+			tokens = append(tokens, token{kind: tokenOperator, value: "!=", pos: i})
+			i += 2
+
+		case r == '"':
+			value, end, err := readString(runes, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{kind: tokenString, value: value, pos: i})
+			i = end
+
+		case isIdentRune(r):
+			start := i
+			for i < len(runes) && isIdentRune(runes[i]) {
+				i++
+			}
+			tokens = append(tokens, identToken(string(runes[start:i]), start))
+
+		default:
+			return nil, fmt.Errorf("unexpected character %q at position %d", r, i)
+		}
+	}
+
+	return append(tokens, token{kind: tokenEOF, pos: len(runes)}), nil
+}
+
+// readString reads the double quoted string starting at runes[start], where
+// \" and \\ escape a quote and a backslash.
+func readString(runes []rune, start int) (string, int, error) {
+	var value strings.Builder
+	for i := start + 1; i < len(runes); i++ {
+		switch runes[i] {
+		case '\\':
+			if i+1 < len(runes) {
+				i++
+			}
+			value.WriteRune(runes[i])
+		case '"':
+			return value.String(), i + 1, nil
+		default:
+			value.WriteRune(runes[i])
+		}
+	}
+
+	return "", 0, fmt.Errorf("unterminated string at position %d", start)
+}
+
+func isIdentRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("_.:-<>", r)
+}
+
+func identToken(value string, pos int) token {
+	switch strings.ToLower(value) {
+	case "and":
+		return token{kind: tokenAnd, value: value, pos: pos}
+	case "or":
+		return token{kind: tokenOr, value: value, pos: pos}
+	case "not":
+		return token{kind: tokenNot, value: value, pos: pos}
+	}
+	return token{kind: tokenIdent, value: value, pos: pos}
+}
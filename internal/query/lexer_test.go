@@ -0,0 +1,88 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []token
+		errMsg   string
+	}{
+		{
+			name:  "Comparisons with keywords",
+			input: `player = "Dono da Bola" AND not (weapon != MOD_RAILGUN or map ~ q3dm)`,
+			expected: []token{
+				{kind: tokenIdent, value: "player", pos: 0},
+				{kind: tokenOperator, value: "=", pos: 7},
+				{kind: tokenString, value: "Dono da Bola", pos: 9},
+				{kind: tokenAnd, value: "AND", pos: 24},
+				{kind: tokenNot, value: "not", pos: 28},
+				{kind: tokenLParen, value: "(", pos: 32},
+				{kind: tokenIdent, value: "weapon", pos: 33},
+				{kind: tokenOperator, value: "!=", pos: 40},
+				{kind: tokenIdent, value: "MOD_RAILGUN", pos: 43},
+				{kind: tokenOr, value: "or", pos: 55},
+				{kind: tokenIdent, value: "map", pos: 58},
+				{kind: tokenOperator, value: "~", pos: 62},
+				{kind: tokenIdent, value: "q3dm", pos: 64},
+				{kind: tokenRParen, value: ")", pos: 68},
+				{kind: tokenEOF, pos: 69},
+			},
+		},
+		{
+			name:  "Unquoted special values",
+			input: `killer=<world> time=12:30`,
+			expected: []token{
+				{kind: tokenIdent, value: "killer", pos: 0},
+				{kind: tokenOperator, value: "=", pos: 6},
+				{kind: tokenIdent, value: "<world>", pos: 7},
+				{kind: tokenIdent, value: "time", pos: 15},
+				{kind: tokenOperator, value: "=", pos: 19},
+				{kind: tokenIdent, value: "12:30", pos: 20},
+				{kind: tokenEOF, pos: 25},
+			},
+		},
+		{
+			name:  "Escaped quotes",
+			input: `"say \"hi\" \\o/"`,
+			expected: []token{
+				{kind: tokenString, value: `say "hi" \o/`, pos: 0},
+				{kind: tokenEOF, pos: 17},
+			},
+		},
+		{
+			name:   "Unterminated string",
+			input:  `player = "Zeh`,
+			errMsg: "unterminated string at position 9",
+		},
+		{
+			name:   "Unexpected character",
+			input:  `player == Zeh && map = q3dm17`,
+			errMsg: "unexpected character '&' at position 14",
+		},
+		{
+			name:   "Incomplete not equal",
+			input:  `player ! Zeh`,
+			errMsg: "unexpected character '!' at position 7",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tokens, err := tokenize(tc.input)
+
+			if tc.errMsg != "" {
+				assert.EqualError(t, err, tc.errMsg)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, tokens)
+		})
+	}
+}
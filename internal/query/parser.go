@@ -0,0 +1,201 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Expr is a parsed filter expression.
+type Expr interface {
+	Match(kill Kill) bool
+}
+
+type andExpr struct{ left, right Expr }
+
+type orExpr struct{ left, right Expr }
+
+type notExpr struct{ expr Expr }
+
+type comparison struct {
+	field    string
+	operator string
+	value    string
+}
+
+func (e andExpr) Match(kill Kill) bool { return e.left.Match(kill) && e.right.Match(kill) }
+
+func (e orExpr) Match(kill Kill) bool { return e.left.Match(kill) || e.right.Match(kill) }
+
+func (e notExpr) Match(kill Kill) bool { return !e.expr.Match(kill) }
+
+// Match compares the field values of the kill with the expected one. Fields
+// with several values, like player, match = and ~ when any of their values
+// does and != when none of them is equal.
+func (c comparison) Match(kill Kill) bool {
+	values := fields[c.field](kill)
+
+	switch c.operator {
+	case "!=":
+		for _, value := range values {
+			if value == c.value {
+				return false
+			}
+		}
+		return true
+	case "~":
+		for _, value := range values {
+			if strings.Contains(strings.ToLower(value), strings.ToLower(c.value)) {
+				return true
+			}
+		}
+		return false
+	default:
+		for _, value := range values {
+			if value == c.value {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Parse parses a filter expression made of comparisons between a field and a
+// value, combined with and, or, not and parentheses:
+//
+//	player = "Zeh" and map = q3dm17 and (weapon = MOD_RAILGUN or weapon ~ rocket)
+//
+// The operators are = (equal), != (not equal) and ~ (contains, ignoring case).
+// An empty expression matches every kill.
+func Parse(input string) (Expr, error) {
+	tokens, err := tokenize(input)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	if p.peek().kind == tokenEOF {
+		return matchAll{}, nil
+	}
+
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if next := p.peek(); next.kind != tokenEOF {
+		return nil, fmt.Errorf("unexpected %s at position %d", next, next.pos)
+	}
+
+	return expr, nil
+}
+
+type matchAll struct{}
+
+func (matchAll) Match(Kill) bool { return true }
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+// parseOr parses: and-expression { "or" and-expression }
+func (p *parser) parseOr() (Expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek().kind == tokenOr {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orExpr{left: left, right: right}
+	}
+
+	return left, nil
+}
+
+// parseAnd parses: unary { "and" unary }
+func (p *parser) parseAnd() (Expr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek().kind == tokenAnd {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = andExpr{left: left, right: right}
+	}
+
+	return left, nil
+}
+
+// parseUnary parses: "not" unary | "(" or-expression ")" | comparison
+func (p *parser) parseUnary() (Expr, error) {
+	switch t := p.peek(); t.kind {
+	case tokenNot:
+		p.next()
+		expr, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notExpr{expr: expr}, nil
+
+	case tokenLParen:
+		p.next()
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokenRParen {
+			return nil, fmt.Errorf("expected \")\" at position %d, got %s", closing.pos, closing)
+		}
+		return expr, nil
+
+	default:
+		return p.parseComparison()
+	}
+}
+
+// parseComparison parses: field operator value
+func (p *parser) parseComparison() (Expr, error) {
+	field := p.next()
+	if field.kind != tokenIdent {
+		return nil, fmt.Errorf("expected a field at position %d, got %s", field.pos, field)
+	}
+
+	name, ok := fieldName(field.value)
+	if !ok {
+		return nil, fmt.Errorf("unknown field %q, expected one of: %s", field.value, strings.Join(FieldNames(), ", "))
+	}
+
+	operator := p.next()
+	if operator.kind != tokenOperator {
+		return nil, fmt.Errorf("expected an operator after %q at position %d, got %s", field.value, operator.pos, operator)
+	}
+
+	value := p.next()
+	if value.kind != tokenIdent && value.kind != tokenString {
+		return nil, fmt.Errorf("expected a value after %q at position %d, got %s", operator.value, value.pos, value)
+	}
+
+	return comparison{field: name, operator: operator.value, value: value.value}, nil
+}
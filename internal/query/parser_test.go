@@ -0,0 +1,82 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vhrboliveira/quake-log-parser-test/pkg/quakelog"
+)
+
+func testKill(killer, victim, method, mapName string) Kill {
+	return Kill{
+		Source:    "games.log",
+		Game:      "game_1",
+		Map:       mapName,
+		Time:      "1:00",
+		KillEvent: quakelog.KillEvent{KillerID: 2, Killer: killer, VictimID: 3, Victim: victim, Method: method},
+	}
+}
+
+func TestParse(t *testing.T) {
+	rail := testKill("Zeh", "Mocinha", quakelog.MOD_RAILGUN, "q3dm17")
+	rocket := testKill("Mocinha", "Zeh", quakelog.MOD_ROCKET_SPLASH, "q3dm17")
+	world := testKill(quakelog.WORLD, "Isgalamido", quakelog.MOD_TRIGGER_HURT, "q3dm6")
+
+	tests := []struct {
+		name     string
+		input    string
+		expected []bool
+	}{
+		{name: "Empty expression", input: "  ", expected: []bool{true, true, true}},
+		{name: "Equal", input: `killer = Zeh`, expected: []bool{true, false, false}},
+		{name: "Not equal", input: `map != q3dm17`, expected: []bool{false, false, true}},
+		{name: "Contains ignoring case", input: `weapon ~ ROCKET`, expected: []bool{false, true, false}},
+		{name: "Player matches killer or victim", input: `player = "Zeh"`, expected: []bool{true, true, false}},
+		{name: "Player not equal matches neither", input: `player != "Zeh"`, expected: []bool{false, false, true}},
+		{name: "Method alias", input: `method = MOD_RAILGUN`, expected: []bool{true, false, false}},
+		{
+			name:     "Request example",
+			input:    `player = "Zeh" and map = "q3dm17" and weapon = MOD_RAILGUN`,
+			expected: []bool{true, false, false},
+		},
+		{name: "And binds tighter than or", input: `killer = Zeh or killer = Mocinha and map = q3dm6`, expected: []bool{true, false, false}},
+		{name: "Parentheses", input: `(killer = Zeh or killer = Mocinha) and map = q3dm17`, expected: []bool{true, true, false}},
+		{name: "Not", input: `not killer = <world>`, expected: []bool{true, true, false}},
+		{name: "Numeric field", input: `killer_id = 2 and victim_id = 3`, expected: []bool{true, true, true}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			expr, err := Parse(tc.input)
+			assert.NoError(t, err)
+
+			got := []bool{expr.Match(rail), expr.Match(rocket), expr.Match(world)}
+			assert.Equal(t, tc.expected, got)
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		errMsg string
+	}{
+		{name: "Unknown field", input: `weapons = MOD_RAILGUN`, errMsg: `unknown field "weapons"`},
+		{name: "Missing operator", input: `killer Zeh`, errMsg: `expected an operator after "killer" at position 7, got "Zeh"`},
+		{name: "Missing value", input: `killer =`, errMsg: `expected a value after "=" at position 8, got end of expression`},
+		{name: "Missing field", input: `= Zeh`, errMsg: `expected a field at position 0, got "="`},
+		{name: "Unclosed parenthesis", input: `(killer = Zeh`, errMsg: `expected ")" at position 13, got end of expression`},
+		{name: "Trailing tokens", input: `killer = Zeh map = q3dm17`, errMsg: `unexpected "map" at position 13`},
+		{name: "Dangling and", input: `killer = Zeh and`, errMsg: `expected a field at position 16, got end of expression`},
+		{name: "Lexer error", input: `killer = "Zeh`, errMsg: `unterminated string at position 9`},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Parse(tc.input)
+
+			assert.ErrorContains(t, err, tc.errMsg)
+		})
+	}
+}
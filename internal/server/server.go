@@ -0,0 +1,139 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+const (
+	defaultLimit = 50
+	maxLimit     = 500
+)
+
+type page[T any] struct {
+	Total  int `json:"total"`
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
+	Items  []T `json:"items"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// New returns the handler of the REST API exposing the matches in store:
+//
+//	GET /matches            matches filtered by map, date, from, to and player
+//	GET /matches/{id}       a single match
+//	GET /players            kills and matches played by each player
+//	GET /players/{name}     the stats of a single player
+//	GET /ranking            players sorted by kills
+//	GET /kills-by-means     kills grouped by means of death
+//
+// Lists accept the limit and offset query parameters for pagination.
+func New(store *Store) http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /matches", func(w http.ResponseWriter, r *http.Request) {
+		writePage(w, r, store.Matches(parseFilter(r)))
+	})
+
+	mux.HandleFunc("GET /matches/{id}", func(w http.ResponseWriter, r *http.Request) {
+		ID, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid match id")
+			return
+		}
+
+		match, ok := store.Match(ID)
+		if !ok {
+			writeError(w, http.StatusNotFound, "match not found")
+			return
+		}
+
+		writeJSON(w, http.StatusOK, match)
+	})
+
+	mux.HandleFunc("GET /players", func(w http.ResponseWriter, r *http.Request) {
+		writePage(w, r, store.Players(parseFilter(r)))
+	})
+
+	mux.HandleFunc("GET /players/{name}", func(w http.ResponseWriter, r *http.Request) {
+		filter := parseFilter(r)
+		filter.Player = r.PathValue("name")
+
+		players := store.Players(filter)
+		if len(players) == 0 {
+			writeError(w, http.StatusNotFound, "player not found")
+			return
+		}
+
+		writeJSON(w, http.StatusOK, players[0])
+	})
+
+	mux.HandleFunc("GET /ranking", func(w http.ResponseWriter, r *http.Request) {
+		writePage(w, r, store.Ranking(parseFilter(r)))
+	})
+
+	mux.HandleFunc("GET /kills-by-means", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, store.KillsByMeans(parseFilter(r)))
+	})
+
+	return mux
+}
+
+func parseFilter(r *http.Request) Filter {
+	query := r.URL.Query()
+	return Filter{
+		Map:    query.Get("map"),
+		Date:   query.Get("date"),
+		From:   query.Get("from"),
+		To:     query.Get("to"),
+		Player: query.Get("player"),
+		Intact: query.Get("intact") == "true",
+	}
+}
+
+func writePage[T any](w http.ResponseWriter, r *http.Request, items []T) {
+	limit, err := queryInt(r, "limit", defaultLimit)
+	if err != nil || limit < 1 || limit > maxLimit {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("limit must be a number between 1 and %d", maxLimit))
+		return
+	}
+
+	offset, err := queryInt(r, "offset", 0)
+	if err != nil || offset < 0 {
+		writeError(w, http.StatusBadRequest, "offset must be a non-negative number")
+		return
+	}
+
+	start := min(offset, len(items))
+	end := min(offset+limit, len(items))
+
+	writeJSON(w, http.StatusOK, page[T]{
+		Total:  len(items),
+		Limit:  limit,
+		Offset: offset,
+		Items:  items[start:end],
+	})
+}
+
+func queryInt(r *http.Request, name string, fallback int) (int, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return fallback, nil
+	}
+	return strconv.Atoi(value)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, errorResponse{Error: message})
+}
+
+func writeJSON(w http.ResponseWriter, status int, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(value)
+}
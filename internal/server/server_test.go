@@ -0,0 +1,120 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		status   int
+		expected string
+	}{
+		{
+			name:     "List matches with pagination",
+			path:     "/matches?limit=1&offset=1",
+			status:   http.StatusOK,
+			expected: `{"total":2,"limit":1,"offset":1,"items":[{"id":2,"game":"game_2","date":"2024-05-02","map":"q3dm6","total_kills":2,"players":["Zeh (ID 4)","Mocinha (ID 2)"],"kills":{"Mocinha (ID 2)":1,"Zeh (ID 4)":-1},"kills_by_means":{"MOD_LAVA":1,"MOD_RAILGUN":1}}]}`,
+		},
+		{
+			name:     "List matches filtered by player",
+			path:     "/matches?player=Mocinha&map=q3dm17",
+			status:   http.StatusOK,
+			expected: `{"total":0,"limit":50,"offset":0,"items":[]}`,
+		},
+		{
+			name:     "Offset beyond the last item",
+			path:     "/matches?offset=10",
+			status:   http.StatusOK,
+			expected: `{"total":2,"limit":50,"offset":10,"items":[]}`,
+		},
+		{
+			name:     "Invalid limit",
+			path:     "/matches?limit=0",
+			status:   http.StatusBadRequest,
+			expected: `{"error":"limit must be a number between 1 and 500"}`,
+		},
+		{
+			name:     "Invalid offset",
+			path:     "/players?offset=-1",
+			status:   http.StatusBadRequest,
+			expected: `{"error":"offset must be a non-negative number"}`,
+		},
+		{
+			name:     "Get match",
+			path:     "/matches/1",
+			status:   http.StatusOK,
+			expected: `{"id":1,"game":"game_1","date":"2024-05-01","map":"q3dm17","total_kills":3,"players":["Zeh (ID 2)","Dono da Bola (ID 3)"],"kills":{"Dono da Bola (ID 3)":0,"Zeh (ID 2)":2},"kills_by_means":{"MOD_FALLING":1,"MOD_RAILGUN":2}}`,
+		},
+		{
+			name:     "Match not found",
+			path:     "/matches/3",
+			status:   http.StatusNotFound,
+			expected: `{"error":"match not found"}`,
+		},
+		{
+			name:     "Invalid match id",
+			path:     "/matches/abc",
+			status:   http.StatusBadRequest,
+			expected: `{"error":"invalid match id"}`,
+		},
+		{
+			name:     "List players",
+			path:     "/players?date=2024-05-02",
+			status:   http.StatusOK,
+			expected: `{"total":2,"limit":50,"offset":0,"items":[{"name":"Mocinha","matches":1,"kills":1},{"name":"Zeh","matches":1,"kills":-1}]}`,
+		},
+		{
+			name:     "Get player",
+			path:     "/players/Zeh",
+			status:   http.StatusOK,
+			expected: `{"name":"Zeh","matches":2,"kills":1}`,
+		},
+		{
+			name:     "Player not found",
+			path:     "/players/Isgalamido",
+			status:   http.StatusNotFound,
+			expected: `{"error":"player not found"}`,
+		},
+		{
+			name:     "Ranking",
+			path:     "/ranking?limit=2",
+			status:   http.StatusOK,
+			expected: `{"total":3,"limit":2,"offset":0,"items":[{"name":"Mocinha","matches":1,"kills":1},{"name":"Zeh","matches":2,"kills":1}]}`,
+		},
+		{
+			name:     "Kills by means",
+			path:     "/kills-by-means?map=q3dm6",
+			status:   http.StatusOK,
+			expected: `{"MOD_LAVA":1,"MOD_RAILGUN":1}`,
+		},
+		{
+			name:     "Unknown route",
+			path:     "/unknown",
+			status:   http.StatusNotFound,
+			expected: "404 page not found",
+		},
+	}
+
+	handler := New(testStore())
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, tc.path, nil))
+
+			assert.Equal(t, tc.status, recorder.Code)
+			if json.Valid(recorder.Body.Bytes()) {
+				assert.JSONEq(t, tc.expected, recorder.Body.String())
+			} else {
+				assert.Contains(t, recorder.Body.String(), tc.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,197 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+
+	"github.com/vhrboliveira/quake-log-parser-test/internal/batch"
+	"github.com/vhrboliveira/quake-log-parser-test/pkg/quakelog"
+)
+
+// playerKey matches the "name (ID n)" keys used by the match reports
+var playerKey = regexp.MustCompile(`^(.*) \(ID (\d+)\)$`)
+
+// Match is a parsed match identified across every served log file. Its date
+// is the modification date of the log it came from, since the log lines only
+// carry the time elapsed since the server started.
+type Match struct {
+	ID   int    `json:"id"`
+	Game string `json:"game"`
+	Date string `json:"date"`
+	quakelog.MatchReport
+}
+
+type PlayerStats struct {
+	Name    string `json:"name"`
+	Matches int    `json:"matches"`
+	Kills   int    `json:"kills"`
+}
+
+type Filter struct {
+	Map    string
+	Date   string
+	From   string
+	To     string
+	Player string
+	// Intact leaves out the matches damaged by a truncated or corrupted log
+	Intact bool
+}
+
+type Store struct {
+	matches []Match
+}
+
+// Load parses the log files at paths with the given parser options, using at
+// most workers goroutines.
+func Load(ctx context.Context, paths []string, workers int, options ...quakelog.Option) (*Store, error) {
+	reports := make([]quakelog.GameReport, len(paths))
+	dates := make([]string, len(paths))
+
+	errs := batch.Each(ctx, paths, workers, func(i int, path string) error {
+		info, err := os.Stat(path)
+		if err != nil {
+			return fmt.Errorf("failed to open quake log file: %w", err)
+		}
+		dates[i] = info.ModTime().Format("2006-01-02")
+
+		reports[i], err = batch.ParseFile(ctx, path, options...)
+		return err
+	})
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	sourceDates := make(map[string]string, len(paths))
+	for i, path := range paths {
+		sourceDates[path] = dates[i]
+	}
+
+	store := &Store{}
+	for i, game := range batch.Merge(paths, reports) {
+		for gameName, report := range game {
+			store.matches = append(store.matches, Match{
+				ID:          i + 1,
+				Game:        gameName,
+				Date:        sourceDates[report.Source],
+				MatchReport: report,
+			})
+		}
+	}
+
+	return store, nil
+}
+
+func NewStore(matches []Match) *Store {
+	return &Store{matches: matches}
+}
+
+func (s *Store) Match(ID int) (Match, bool) {
+	for _, match := range s.matches {
+		if match.ID == ID {
+			return match, true
+		}
+	}
+	return Match{}, false
+}
+
+func (s *Store) Matches(filter Filter) []Match {
+	matches := make([]Match, 0)
+	for _, match := range s.matches {
+		if filter.matches(match) {
+			matches = append(matches, match)
+		}
+	}
+	return matches
+}
+
+// Players aggregates the kills of every player in the filtered matches,
+// sorted by name. Players are identified by name, since their IDs are only
+// the server slots they used in each match.
+func (s *Store) Players(filter Filter) []PlayerStats {
+	stats := make(map[string]*PlayerStats)
+	for _, match := range s.Matches(Filter{Map: filter.Map, Date: filter.Date, From: filter.From, To: filter.To, Intact: filter.Intact}) {
+		for key, kills := range match.Kills {
+			name := PlayerName(key)
+			if filter.Player != "" && name != filter.Player {
+				continue
+			}
+			if _, ok := stats[name]; !ok {
+				stats[name] = &PlayerStats{Name: name}
+			}
+			stats[name].Matches++
+			stats[name].Kills += kills
+		}
+	}
+
+	players := make([]PlayerStats, 0, len(stats))
+	for _, player := range stats {
+		players = append(players, *player)
+	}
+	sort.Slice(players, func(i, j int) bool {
+		return players[i].Name < players[j].Name
+	})
+
+	return players
+}
+
+// Ranking returns the players sorted by kills, highest first.
+func (s *Store) Ranking(filter Filter) []PlayerStats {
+	players := s.Players(filter)
+	sort.SliceStable(players, func(i, j int) bool {
+		return players[i].Kills > players[j].Kills
+	})
+	return players
+}
+
+func (s *Store) KillsByMeans(filter Filter) map[string]int {
+	killsByMeans := make(map[string]int)
+	for _, match := range s.Matches(filter) {
+		for method, kills := range match.KillsByMeans {
+			killsByMeans[method] += kills
+		}
+	}
+	return killsByMeans
+}
+
+// PlayerName strips the " (ID n)" suffix of a report player key.
+func PlayerName(key string) string {
+	if matches := playerKey.FindStringSubmatch(key); matches != nil {
+		return matches[1]
+	}
+	return key
+}
+
+func (f Filter) matches(match Match) bool {
+	if f.Map != "" && match.Map != f.Map {
+		return false
+	}
+	if f.Date != "" && match.Date != f.Date {
+		return false
+	}
+	if f.From != "" && match.Date < f.From {
+		return false
+	}
+	if f.To != "" && match.Date > f.To {
+		return false
+	}
+	if f.Intact && match.IntegrityStatus() != quakelog.INTEGRITY_OK {
+		return false
+	}
+	if f.Player != "" {
+		for key := range match.Kills {
+			if PlayerName(key) == f.Player {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
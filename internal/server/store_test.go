@@ -0,0 +1,126 @@
+package server
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vhrboliveira/quake-log-parser-test/pkg/quakelog"
+)
+
+func testStore() *Store {
+	return NewStore([]Match{
+		{
+			ID:   1,
+			Game: "game_1",
+			Date: "2024-05-01",
+			MatchReport: quakelog.MatchReport{
+				Map:          "q3dm17",
+				TotalKills:   3,
+				Players:      []string{"Zeh (ID 2)", "Dono da Bola (ID 3)"},
+				Kills:        map[string]int{"Zeh (ID 2)": 2, "Dono da Bola (ID 3)": 0},
+				KillsByMeans: map[string]int{quakelog.MOD_RAILGUN: 2, quakelog.MOD_FALLING: 1},
+			},
+		},
+		{
+			ID:   2,
+			Game: "game_2",
+			Date: "2024-05-02",
+			MatchReport: quakelog.MatchReport{
+				Map:          "q3dm6",
+				TotalKills:   2,
+				Players:      []string{"Zeh (ID 4)", "Mocinha (ID 2)"},
+				Kills:        map[string]int{"Zeh (ID 4)": -1, "Mocinha (ID 2)": 1},
+				KillsByMeans: map[string]int{quakelog.MOD_RAILGUN: 1, quakelog.MOD_LAVA: 1},
+			},
+		},
+	})
+}
+
+func TestStoreMatches(t *testing.T) {
+	tests := []struct {
+		name     string
+		filter   Filter
+		expected []int
+	}{
+		{name: "No filter", filter: Filter{}, expected: []int{1, 2}},
+		{name: "By map", filter: Filter{Map: "q3dm6"}, expected: []int{2}},
+		{name: "By date", filter: Filter{Date: "2024-05-01"}, expected: []int{1}},
+		{name: "By date range", filter: Filter{From: "2024-05-02", To: "2024-05-31"}, expected: []int{2}},
+		{name: "By player", filter: Filter{Player: "Dono da Bola"}, expected: []int{1}},
+		{name: "No matches", filter: Filter{Player: "Isgalamido"}, expected: []int{}},
+	}
+
+	store := testStore()
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			IDs := make([]int, 0)
+			for _, match := range store.Matches(tc.filter) {
+				IDs = append(IDs, match.ID)
+			}
+
+			assert.Equal(t, tc.expected, IDs)
+		})
+	}
+}
+
+func TestStoreAggregates(t *testing.T) {
+	store := testStore()
+
+	assert.Equal(t, []PlayerStats{
+		{Name: "Dono da Bola", Matches: 1, Kills: 0},
+		{Name: "Mocinha", Matches: 1, Kills: 1},
+		{Name: "Zeh", Matches: 2, Kills: 1},
+	}, store.Players(Filter{}))
+
+	assert.Equal(t, []PlayerStats{
+		{Name: "Zeh", Matches: 1, Kills: 2},
+		{Name: "Dono da Bola", Matches: 1, Kills: 0},
+	}, store.Ranking(Filter{Map: "q3dm17"}))
+
+	assert.Equal(t, map[string]int{
+		quakelog.MOD_RAILGUN: 3,
+		quakelog.MOD_FALLING: 1,
+		quakelog.MOD_LAVA:    1,
+	}, store.KillsByMeans(Filter{}))
+
+	match, ok := store.Match(2)
+	assert.True(t, ok)
+	assert.Equal(t, "q3dm6", match.Map)
+
+	_, ok = store.Match(3)
+	assert.False(t, ok)
+}
+
+func TestLoad(t *testing.T) {
+	content, err := os.ReadFile("../../assets/test.log")
+	assert.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "server.log")
+	assert.NoError(t, os.WriteFile(path, content, 0o644))
+	date := time.Date(2024, 5, 1, 12, 0, 0, 0, time.Local)
+	assert.NoError(t, os.Chtimes(path, date, date))
+
+	store, err := Load(context.Background(), []string{path, path}, 2)
+	assert.NoError(t, err)
+
+	matches := store.Matches(Filter{})
+	assert.Len(t, matches, 6)
+	assert.Equal(t, 4, matches[3].ID)
+	assert.Equal(t, "game_4", matches[3].Game)
+	assert.Equal(t, "2024-05-01", matches[3].Date)
+	assert.Equal(t, "Q3TOURNEY6_CTF", matches[3].Map)
+	assert.Equal(t, path, matches[3].Source)
+	assert.Len(t, store.Matches(Filter{Intact: true}), 2, "The match without ShutdownGame and the one with a clock going back are left out")
+
+	_, err = Load(context.Background(), []string{"/nonexistent/file.log"}, 1)
+	assert.ErrorContains(t, err, "failed to open quake log file")
+}
+
+func TestPlayerName(t *testing.T) {
+	assert.Equal(t, "Dono da Bola", PlayerName("Dono da Bola (ID 3)"))
+	assert.Equal(t, "Player (ID x)", PlayerName("Player (ID x)"))
+}
@@ -0,0 +1,116 @@
+package storage
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMigrate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "quake.db")
+
+	migrations, err := loadMigrations()
+	assert.NoError(t, err)
+	assert.NotEmpty(t, migrations)
+	for i := 1; i < len(migrations); i++ {
+		assert.Less(t, migrations[i-1].version, migrations[i].version, "Migration versions must increase")
+	}
+	latest := migrations[len(migrations)-1].version
+
+	db, err := sql.Open("sqlite3", path)
+	assert.NoError(t, err)
+	defer db.Close()
+
+	// running the migrations again on an up to date database is a no-op
+	for range 2 {
+		assert.NoError(t, migrate(db))
+
+		var version, count int
+		assert.NoError(t, db.QueryRow("SELECT MAX(version), COUNT(*) FROM schema_migrations").Scan(&version, &count))
+		assert.Equal(t, latest, version)
+		assert.Equal(t, len(migrations), count)
+	}
+
+	for _, table := range []string{"logs", "matches", "log_matches", "match_players", "player_sessions", "kills"} {
+		var name string
+		assert.NoError(t, db.QueryRow("SELECT name FROM sqlite_master WHERE type = 'table' AND name = ?", table).Scan(&name))
+	}
+}
+
+func TestMigrateOffsets(t *testing.T) {
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "quake.db"))
+	assert.NoError(t, err)
+	defer db.Close()
+
+	// a database of the first schema, which only had the times of the log
+	migrations, err := loadMigrations()
+	assert.NoError(t, err)
+	_, err = db.Exec(`CREATE TABLE schema_migrations (version INTEGER PRIMARY KEY, applied_at TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP)`)
+	assert.NoError(t, err)
+	assert.NoError(t, applyMigration(db, migrations[0]))
+	for _, statement := range []string{
+		"INSERT INTO logs (id, path, hash, ingested_at) VALUES (1, '/games.log', 'abc', '2024-05-01T10:00:00Z')",
+		"INSERT INTO matches (id, log_id, game, map, started_at, ended_at, total_kills) VALUES (1, 1, 'game_1', 'q3dm17', '1:47', '12:13', 1)",
+		"INSERT INTO player_sessions (match_id, client_id, name, connected_at) VALUES (1, 2, 'Zeh', '1:50')",
+		"INSERT INTO kills (match_id, time, killer_id, killer, victim_id, victim, method) VALUES (1, '2:05', 2, 'Zeh', 3, 'Mocinha', 'MOD_RAILGUN')",
+	} {
+		_, err := db.Exec(statement)
+		assert.NoError(t, err)
+	}
+
+	assert.NoError(t, migrate(db))
+
+	var ingestedAt string
+	var duration, offset, connected int
+	var disconnected sql.NullInt64
+	assert.NoError(t, db.QueryRow("SELECT ingested_at, duration_seconds FROM matches").Scan(&ingestedAt, &duration))
+	assert.NoError(t, db.QueryRow("SELECT offset_seconds FROM kills").Scan(&offset))
+	assert.NoError(t, db.QueryRow("SELECT connected_offset_seconds, disconnected_offset_seconds FROM player_sessions").
+		Scan(&connected, &disconnected))
+	assert.Equal(t, "2024-05-01T10:00:00Z", ingestedAt)
+	assert.Equal(t, 626, duration)
+	assert.Equal(t, 18, offset)
+	assert.Equal(t, 3, connected)
+	assert.False(t, disconnected.Valid)
+
+	// rebuilding the matches table keeps their rows and links them to their log
+	var logID int
+	var game string
+	assert.NoError(t, db.QueryRow("SELECT log_id, game FROM log_matches WHERE match_id = 1").Scan(&logID, &game))
+	assert.Equal(t, 1, logID)
+	assert.Equal(t, "game_1", game)
+}
+
+func TestMigrateForeignKeys(t *testing.T) {
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "quake.db")+"?_foreign_keys=on")
+	assert.NoError(t, err)
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	migrations, err := loadMigrations()
+	assert.NoError(t, err)
+	_, err = db.Exec(`CREATE TABLE schema_migrations (version INTEGER PRIMARY KEY, applied_at TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP)`)
+	assert.NoError(t, err)
+	for _, m := range migrations[:2] {
+		assert.NoError(t, applyMigration(db, m))
+	}
+	for _, statement := range []string{
+		"INSERT INTO logs (id, path, hash, ingested_at) VALUES (1, '/games.log', 'abc', '2024-05-01T10:00:00Z')",
+		"INSERT INTO matches (id, log_id, game, map, started_at, ended_at, total_kills) VALUES (1, 1, 'game_1', 'q3dm17', '1:47', '12:13', 1)",
+		"INSERT INTO kills (match_id, time, killer_id, killer, victim_id, victim, method) VALUES (1, '2:05', 2, 'Zeh', 3, 'Mocinha', 'MOD_RAILGUN')",
+	} {
+		_, err := db.Exec(statement)
+		assert.NoError(t, err)
+	}
+
+	assert.NoError(t, migrate(db))
+
+	var kills int
+	var foreignKeys bool
+	assert.NoError(t, db.QueryRow("SELECT COUNT(*) FROM kills").Scan(&kills))
+	assert.Equal(t, 1, kills, "Rebuilding the matches table does not cascade to their kills")
+	assert.NoError(t, db.QueryRow("PRAGMA foreign_keys").Scan(&foreignKeys))
+	assert.True(t, foreignKeys, "The foreign keys are enabled again after the migrations")
+}
@@ -0,0 +1,50 @@
+CREATE TABLE logs (
+    id          INTEGER PRIMARY KEY,
+    path        TEXT NOT NULL UNIQUE,
+    hash        TEXT NOT NULL,
+    ingested_at TEXT NOT NULL
+);
+
+CREATE TABLE matches (
+    id          INTEGER PRIMARY KEY,
+    log_id      INTEGER NOT NULL REFERENCES logs (id) ON DELETE CASCADE,
+    game        TEXT NOT NULL,
+    map         TEXT NOT NULL,
+    started_at  TEXT NOT NULL,
+    ended_at    TEXT NOT NULL,
+    total_kills INTEGER NOT NULL,
+    UNIQUE (log_id, game)
+);
+
+CREATE TABLE match_players (
+    match_id  INTEGER NOT NULL REFERENCES matches (id) ON DELETE CASCADE,
+    client_id INTEGER NOT NULL,
+    name      TEXT NOT NULL,
+    kills     INTEGER NOT NULL,
+    PRIMARY KEY (match_id, client_id)
+);
+
+CREATE TABLE player_sessions (
+    id              INTEGER PRIMARY KEY,
+    match_id        INTEGER NOT NULL REFERENCES matches (id) ON DELETE CASCADE,
+    client_id       INTEGER NOT NULL,
+    name            TEXT NOT NULL,
+    connected_at    TEXT NOT NULL,
+    disconnected_at TEXT
+);
+
+CREATE TABLE kills (
+    id        INTEGER PRIMARY KEY,
+    match_id  INTEGER NOT NULL REFERENCES matches (id) ON DELETE CASCADE,
+    time      TEXT NOT NULL,
+    killer_id INTEGER NOT NULL,
+    killer    TEXT NOT NULL,
+    victim_id INTEGER NOT NULL,
+    victim    TEXT NOT NULL,
+    method    TEXT NOT NULL
+);
+
+CREATE INDEX kills_match_id ON kills (match_id);
+CREATE INDEX kills_killer ON kills (killer);
+CREATE INDEX kills_victim ON kills (victim);
+CREATE INDEX player_sessions_match_id ON player_sessions (match_id);
@@ -0,0 +1,40 @@
+-- logs are identified by the hash of their content, wherever they are
+-- ingested from, and matches by their fingerprint, so the matches shared by
+-- overlapping logs are only stored once
+CREATE INDEX logs_hash ON logs (hash);
+
+ALTER TABLE matches ADD COLUMN fingerprint TEXT;
+ALTER TABLE matches ADD COLUMN ingested_at TEXT;
+ALTER TABLE matches ADD COLUMN duration_seconds INTEGER;
+CREATE UNIQUE INDEX matches_fingerprint ON matches (fingerprint);
+
+-- the times of the log are a clock in minutes:seconds, so kills and sessions
+-- also store their offset in seconds from the start of the match
+ALTER TABLE kills ADD COLUMN offset_seconds INTEGER;
+ALTER TABLE player_sessions ADD COLUMN connected_offset_seconds INTEGER;
+ALTER TABLE player_sessions ADD COLUMN disconnected_offset_seconds INTEGER;
+
+UPDATE matches SET
+    ingested_at = (SELECT ingested_at FROM logs WHERE logs.id = matches.log_id),
+    duration_seconds = (CAST(substr(ended_at, 1, instr(ended_at, ':') - 1) AS INTEGER) * 60 + CAST(substr(ended_at, instr(ended_at, ':') + 1) AS INTEGER))
+        - (CAST(substr(started_at, 1, instr(started_at, ':') - 1) AS INTEGER) * 60 + CAST(substr(started_at, instr(started_at, ':') + 1) AS INTEGER));
+
+UPDATE kills SET offset_seconds = (
+    SELECT (CAST(substr(kills.time, 1, instr(kills.time, ':') - 1) AS INTEGER) * 60 + CAST(substr(kills.time, instr(kills.time, ':') + 1) AS INTEGER))
+        - (CAST(substr(m.started_at, 1, instr(m.started_at, ':') - 1) AS INTEGER) * 60 + CAST(substr(m.started_at, instr(m.started_at, ':') + 1) AS INTEGER))
+    FROM matches m WHERE m.id = kills.match_id
+);
+
+UPDATE player_sessions SET
+    connected_offset_seconds = (
+        SELECT (CAST(substr(player_sessions.connected_at, 1, instr(player_sessions.connected_at, ':') - 1) AS INTEGER) * 60 + CAST(substr(player_sessions.connected_at, instr(player_sessions.connected_at, ':') + 1) AS INTEGER))
+            - (CAST(substr(m.started_at, 1, instr(m.started_at, ':') - 1) AS INTEGER) * 60 + CAST(substr(m.started_at, instr(m.started_at, ':') + 1) AS INTEGER))
+        FROM matches m WHERE m.id = player_sessions.match_id
+    ),
+    disconnected_offset_seconds = (
+        SELECT (CAST(substr(player_sessions.disconnected_at, 1, instr(player_sessions.disconnected_at, ':') - 1) AS INTEGER) * 60 + CAST(substr(player_sessions.disconnected_at, instr(player_sessions.disconnected_at, ':') + 1) AS INTEGER))
+            - (CAST(substr(m.started_at, 1, instr(m.started_at, ':') - 1) AS INTEGER) * 60 + CAST(substr(m.started_at, instr(m.started_at, ':') + 1) AS INTEGER))
+        FROM matches m WHERE m.id = player_sessions.match_id
+    );
+
+CREATE INDEX matches_ingested_at ON matches (ingested_at);
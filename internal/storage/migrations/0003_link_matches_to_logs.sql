@@ -0,0 +1,36 @@
+-- a match can be read from several overlapping logs, so instead of belonging
+-- to the first log it was stored from, it is linked to every log it is found
+-- in, and replacing a log only removes the matches no other log links to.
+-- The matches an overlapping log shared with one stored before it were not
+-- recorded for it, so they are only linked once that log is ingested again.
+CREATE TABLE matches_new (
+    id               INTEGER PRIMARY KEY,
+    fingerprint      TEXT,
+    map              TEXT NOT NULL,
+    started_at       TEXT NOT NULL,
+    ended_at         TEXT NOT NULL,
+    duration_seconds INTEGER,
+    ingested_at      TEXT,
+    total_kills      INTEGER NOT NULL
+);
+
+INSERT INTO matches_new (id, fingerprint, map, started_at, ended_at, duration_seconds, ingested_at, total_kills)
+    SELECT id, fingerprint, map, started_at, ended_at, duration_seconds, ingested_at, total_kills FROM matches;
+
+-- game is the name of the match in the report of the log
+CREATE TABLE log_matches (
+    log_id   INTEGER NOT NULL REFERENCES logs (id) ON DELETE CASCADE,
+    match_id INTEGER NOT NULL REFERENCES matches (id) ON DELETE CASCADE,
+    game     TEXT NOT NULL,
+    PRIMARY KEY (log_id, match_id),
+    UNIQUE (log_id, game)
+);
+
+INSERT INTO log_matches (log_id, match_id, game) SELECT log_id, id, game FROM matches;
+
+DROP TABLE matches;
+ALTER TABLE matches_new RENAME TO matches;
+
+CREATE UNIQUE INDEX matches_fingerprint ON matches (fingerprint);
+CREATE INDEX matches_ingested_at ON matches (ingested_at);
+CREATE INDEX log_matches_match_id ON log_matches (match_id);
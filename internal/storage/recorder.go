@@ -0,0 +1,114 @@
+package storage
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/vhrboliveira/quake-log-parser-test/pkg/quakelog"
+)
+
+type match struct {
+	game        string
+	fingerprint string
+	mapName     string
+	startedAt   string
+	endedAt     string
+	totalKills  int
+	players     []quakelog.ScoreboardEntry
+	sessions    []*session
+	kills       []kill
+}
+
+type session struct {
+	clientID       int
+	name           string
+	connectedAt    string
+	disconnectedAt string
+}
+
+type kill struct {
+	time string
+	quakelog.KillEvent
+}
+
+// recorder rebuilds the matches of a log, with their player sessions and
+// kills, from the events decoded by the parser. Only finished matches are
+// kept, like in the game reports.
+type recorder struct {
+	current  *match
+	sessions map[int]*session
+	matches  []*match
+}
+
+func (r *recorder) handleEvent(event quakelog.Event, scoreboard quakelog.Scoreboard) {
+	switch event.Type {
+	case quakelog.EVENT_MATCH_START:
+		r.current = &match{game: event.Game, mapName: scoreboard.Map, startedAt: event.Time}
+		r.sessions = make(map[int]*session)
+
+	case quakelog.EVENT_JOIN:
+		r.openSession(event.Player.ID, event.Player.Name, event.Time)
+
+	case quakelog.EVENT_PLAYER_INFO:
+		// players already in the server when the match started only send
+		// their user info, without a ClientConnect
+		if s, ok := r.sessions[event.Player.ID]; ok {
+			s.name = event.Player.Name
+		} else {
+			r.openSession(event.Player.ID, event.Player.Name, event.Time)
+		}
+
+	case quakelog.EVENT_LEAVE:
+		r.closeSession(event.Player.ID, event.Time)
+
+	case quakelog.EVENT_KILL:
+		if r.current != nil {
+			r.current.kills = append(r.current.kills, kill{time: event.Time, KillEvent: *event.Kill})
+		}
+
+	case quakelog.EVENT_MATCH_END:
+		if r.current == nil {
+			return
+		}
+		for clientID := range r.sessions {
+			r.closeSession(clientID, event.Time)
+		}
+		r.current.endedAt = event.Time
+		r.current.totalKills = event.Match.TotalKills
+		r.current.fingerprint = event.Match.Fingerprint
+		r.current.players = scoreboard.Players
+		r.matches = append(r.matches, r.current)
+		r.current = nil
+	}
+}
+
+func (r *recorder) openSession(clientID int, name, time string) {
+	if r.current == nil {
+		return
+	}
+
+	r.closeSession(clientID, time)
+	s := &session{clientID: clientID, name: name, connectedAt: time}
+	r.sessions[clientID] = s
+	r.current.sessions = append(r.current.sessions, s)
+}
+
+func (r *recorder) closeSession(clientID int, time string) {
+	if s, ok := r.sessions[clientID]; ok {
+		s.disconnectedAt = time
+		delete(r.sessions, clientID)
+	}
+}
+
+// offset returns the seconds elapsed from the start of the match to time,
+// both being a clock formatted as minutes:seconds.
+func (m *match) offset(time string) int {
+	return seconds(time) - seconds(m.startedAt)
+}
+
+func seconds(time string) int {
+	minutes, secs, _ := strings.Cut(time, ":")
+	m, _ := strconv.Atoi(minutes)
+	s, _ := strconv.Atoi(secs)
+	return m*60 + s
+}
@@ -0,0 +1,51 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vhrboliveira/quake-log-parser-test/pkg/quakelog"
+)
+
+func TestRecorder(t *testing.T) {
+	r := &recorder{}
+	scoreboard := quakelog.Scoreboard{Map: "q3dm17"}
+	final := quakelog.Scoreboard{Players: []quakelog.ScoreboardEntry{{ID: 2, Name: "Zeh", Kills: 1}}}
+	killEvent := &quakelog.KillEvent{KillerID: 2, Killer: "Zeh", VictimID: 3, Victim: "Mocinha", Method: quakelog.MOD_RAILGUN}
+
+	events := []quakelog.Event{
+		// events outside of a match are ignored
+		{Type: quakelog.EVENT_JOIN, Time: "0:00", Player: &quakelog.PlayerEvent{ID: 5}},
+		{Type: quakelog.EVENT_MATCH_START, Time: "0:01", Game: "game_1"},
+		{Type: quakelog.EVENT_PLAYER_INFO, Time: "0:01", Player: &quakelog.PlayerEvent{ID: 2, Name: "Zeh"}},
+		{Type: quakelog.EVENT_JOIN, Time: "0:02", Player: &quakelog.PlayerEvent{ID: 3}},
+		{Type: quakelog.EVENT_PLAYER_INFO, Time: "0:02", Player: &quakelog.PlayerEvent{ID: 3, Name: "Mocinha"}},
+		{Type: quakelog.EVENT_KILL, Time: "0:03", Kill: killEvent},
+		{Type: quakelog.EVENT_LEAVE, Time: "0:04", Player: &quakelog.PlayerEvent{ID: 3, Name: "Mocinha"}},
+		{Type: quakelog.EVENT_JOIN, Time: "0:05", Player: &quakelog.PlayerEvent{ID: 3, Name: "Mocinha"}},
+		{Type: quakelog.EVENT_MATCH_END, Time: "0:06", Game: "game_1", Match: &quakelog.MatchReport{TotalKills: 1}},
+		{Type: quakelog.EVENT_MATCH_START, Time: "0:07", Game: "game_2"},
+	}
+	for i, event := range events {
+		if i == len(events)-2 {
+			r.handleEvent(event, final)
+		} else {
+			r.handleEvent(event, scoreboard)
+		}
+	}
+
+	assert.Len(t, r.matches, 1, "Matches in progress are not recorded")
+	m := r.matches[0]
+	assert.Equal(t, "game_1", m.game)
+	assert.Equal(t, "q3dm17", m.mapName)
+	assert.Equal(t, "0:01", m.startedAt)
+	assert.Equal(t, "0:06", m.endedAt)
+	assert.Equal(t, 1, m.totalKills)
+	assert.Equal(t, final.Players, m.players)
+	assert.Equal(t, []kill{{time: "0:03", KillEvent: *killEvent}}, m.kills)
+	assert.Equal(t, []*session{
+		{clientID: 2, name: "Zeh", connectedAt: "0:01", disconnectedAt: "0:06"},
+		{clientID: 3, name: "Mocinha", connectedAt: "0:02", disconnectedAt: "0:04"},
+		{clientID: 3, name: "Mocinha", connectedAt: "0:05", disconnectedAt: "0:06"},
+	}, m.sessions)
+}
@@ -0,0 +1,256 @@
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+
+	"github.com/vhrboliveira/quake-log-parser-test/pkg/quakelog"
+)
+
+// Store persists the matches, player sessions and kills of the ingested logs
+// into a SQLite database.
+type Store struct {
+	db *sql.DB
+}
+
+// Open opens the SQLite database at path, creating it if needed, and brings
+// its schema up to date.
+func Open(path string) (*Store, error) {
+	db, err := sql.Open("sqlite3", path+"?_foreign_keys=on&_busy_timeout=5000")
+	if err != nil {
+		return nil, fmt.Errorf("error opening database: %w", err)
+	}
+
+	if err := migrate(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &Store{db: db}, nil
+}
+
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Ingest parses the log at path with the given parser options and stores its
+// matches. Logs are identified
+// by the hash of their content: ingesting a log already stored, from any
+// path, is a no-op, and a log that changed replaces everything stored for its
+// path. Matches are identified by their quakelog fingerprint, so the ones
+// already stored from an overlapping log are linked to both logs instead of
+// being stored twice, and are kept until no stored log contains them. It
+// reports whether the database was changed, which never happens when ctx is
+// done before the log is fully parsed.
+func (s *Store) Ingest(ctx context.Context, path string, options ...quakelog.Option) (bool, error) {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return false, fmt.Errorf("failed to open quake log file: %w", err)
+	}
+
+	hash, err := hashFile(path)
+	if err != nil {
+		return false, err
+	}
+
+	var stored int
+	if err := s.db.QueryRow("SELECT COUNT(*) FROM logs WHERE hash = ?", hash).Scan(&stored); err != nil {
+		return false, fmt.Errorf("error reading database: %w", err)
+	}
+	if stored > 0 {
+		return false, nil
+	}
+
+	// the log is stored under the hash of the bytes parsed, which differ from
+	// the ones hashed above when the log grew in between
+	matches, hash, err := record(ctx, path, options)
+	if err != nil {
+		return false, err
+	}
+
+	if err := s.save(path, hash, matches); err != nil {
+		return false, fmt.Errorf("error writing database: %w", err)
+	}
+
+	return true, nil
+}
+
+func hashFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open quake log file: %w", err)
+	}
+	defer f.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return "", fmt.Errorf("error reading file: %w", err)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// record parses the log at path, returning its matches and the hash of the
+// bytes they were parsed from.
+func record(ctx context.Context, path string, options []quakelog.Option) ([]*match, string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to open quake log file: %w", err)
+	}
+	defer f.Close()
+
+	r := &recorder{}
+	hash := sha256.New()
+
+	options = append(options[:len(options):len(options)], quakelog.OnEvent(r.handleEvent), quakelog.WithFingerprints())
+	if _, err := quakelog.New(options...).Parse(io.TeeReader(contextReader{ctx: ctx, r: f}, hash)); err != nil {
+		return nil, "", err
+	}
+
+	// a partial parse must not be stored under the hash of the whole log
+	if err := ctx.Err(); err != nil {
+		return nil, "", err
+	}
+
+	return r.matches, hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// contextReader stops reading once ctx is done.
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (r contextReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	return r.r.Read(p)
+}
+
+func (s *Store) save(path, hash string, matches []*match) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// the links of a changed log to its matches are removed by the cascade
+	if _, err := tx.Exec("DELETE FROM logs WHERE path = ?", path); err != nil {
+		return err
+	}
+
+	ingestedAt := time.Now().UTC().Format(time.RFC3339)
+	result, err := tx.Exec("INSERT INTO logs (path, hash, ingested_at) VALUES (?, ?, ?)", path, hash, ingestedAt)
+	if err != nil {
+		return err
+	}
+	logID, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	for _, m := range matches {
+		if err := saveMatch(tx, logID, ingestedAt, m); err != nil {
+			return err
+		}
+	}
+
+	// the matches of the previous content of the log found in no other log
+	if _, err := tx.Exec("DELETE FROM matches WHERE id NOT IN (SELECT match_id FROM log_matches)"); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// saveMatch links m to the log, storing it unless a match with the same
+// fingerprint is already stored.
+func saveMatch(tx *sql.Tx, logID int64, ingestedAt string, m *match) error {
+	matchID, err := findMatch(tx, m.fingerprint)
+	if err != nil {
+		return err
+	}
+
+	if matchID == 0 {
+		if matchID, err = insertMatch(tx, ingestedAt, m); err != nil {
+			return err
+		}
+	}
+
+	// a log holding the same match twice only links it once
+	_, err = tx.Exec(`INSERT INTO log_matches (log_id, match_id, game) VALUES (?, ?, ?)
+		ON CONFLICT (log_id, match_id) DO NOTHING`, logID, matchID, m.game)
+	return err
+}
+
+// findMatch returns the ID of the match stored with fingerprint, or 0 if there
+// is none. Matches without a fingerprint are never found.
+func findMatch(tx *sql.Tx, fingerprint string) (int64, error) {
+	if fingerprint == "" {
+		return 0, nil
+	}
+
+	var matchID int64
+	err := tx.QueryRow("SELECT id FROM matches WHERE fingerprint = ?", fingerprint).Scan(&matchID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+
+	return matchID, err
+}
+
+func insertMatch(tx *sql.Tx, ingestedAt string, m *match) (int64, error) {
+	result, err := tx.Exec(`INSERT INTO matches
+		(fingerprint, map, started_at, ended_at, duration_seconds, ingested_at, total_kills)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`, nullString(m.fingerprint), m.mapName,
+		m.startedAt, m.endedAt, m.offset(m.endedAt), ingestedAt, m.totalKills)
+	if err != nil {
+		return 0, err
+	}
+	matchID, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	for _, player := range m.players {
+		if _, err := tx.Exec("INSERT INTO match_players (match_id, client_id, name, kills) VALUES (?, ?, ?, ?)",
+			matchID, player.ID, player.Name, player.Kills); err != nil {
+			return 0, err
+		}
+	}
+
+	for _, s := range m.sessions {
+		disconnectedOffset := sql.NullInt64{Int64: int64(m.offset(s.disconnectedAt)), Valid: s.disconnectedAt != ""}
+		if _, err := tx.Exec(`INSERT INTO player_sessions
+			(match_id, client_id, name, connected_at, disconnected_at, connected_offset_seconds, disconnected_offset_seconds)
+			VALUES (?, ?, ?, ?, ?, ?, ?)`, matchID, s.clientID, s.name, s.connectedAt, nullString(s.disconnectedAt),
+			m.offset(s.connectedAt), disconnectedOffset); err != nil {
+			return 0, err
+		}
+	}
+
+	for _, k := range m.kills {
+		if _, err := tx.Exec(`INSERT INTO kills (match_id, time, offset_seconds, killer_id, killer, victim_id, victim, method)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?)`, matchID, k.time, m.offset(k.time), k.KillerID, k.Killer, k.VictimID, k.Victim, k.Method); err != nil {
+			return 0, err
+		}
+	}
+
+	return matchID, nil
+}
+
+func nullString(value string) sql.NullString {
+	return sql.NullString{String: value, Valid: value != ""}
+}
@@ -0,0 +1,210 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func countRows(t *testing.T, store *Store, query string, args ...any) int {
+	t.Helper()
+
+	var count int
+	assert.NoError(t, store.db.QueryRow(query, args...).Scan(&count))
+	return count
+}
+
+func TestIngest(t *testing.T) {
+	content, err := os.ReadFile("../../assets/test.log")
+	assert.NoError(t, err)
+
+	tmpdir := t.TempDir()
+	path := filepath.Join(tmpdir, "games.log")
+	assert.NoError(t, os.WriteFile(path, content, 0o644))
+
+	store, err := Open(filepath.Join(tmpdir, "quake.db"))
+	assert.NoError(t, err)
+	defer store.Close()
+
+	changed, err := store.Ingest(context.Background(), path)
+	assert.NoError(t, err)
+	assert.True(t, changed)
+
+	assert.Equal(t, 1, countRows(t, store, "SELECT COUNT(*) FROM logs"))
+	assert.Equal(t, 3, countRows(t, store, "SELECT COUNT(*) FROM matches"))
+	assert.Equal(t, 21, countRows(t, store, "SELECT COUNT(*) FROM kills"))
+	assert.Equal(t, 9, countRows(t, store, "SELECT COUNT(*) FROM match_players"))
+	assert.Equal(t, 8, countRows(t, store, "SELECT COUNT(*) FROM kills WHERE method = 'MOD_ROCKET_SPLASH' AND match_id = (SELECT match_id FROM log_matches WHERE game = 'game_2')"))
+
+	var mapName, startedAt, endedAt string
+	var totalKills int
+	assert.NoError(t, store.db.QueryRow("SELECT map, started_at, ended_at, total_kills FROM matches m JOIN log_matches l ON l.match_id = m.id WHERE l.game = 'game_3'").
+		Scan(&mapName, &startedAt, &endedAt, &totalKills))
+	assert.Equal(t, "Q3TOURNEY6_CTF", mapName)
+	assert.Equal(t, "20:01", startedAt)
+	assert.Equal(t, "10:28", endedAt)
+	assert.Equal(t, 11, totalKills)
+
+	var kills int
+	assert.NoError(t, store.db.QueryRow(`SELECT p.kills FROM match_players p JOIN log_matches l ON l.match_id = p.match_id
+		WHERE l.game = 'game_3' AND p.name = 'Mocinha'`).Scan(&kills))
+	assert.Equal(t, -2, kills)
+
+	// re-ingesting the same content is a no-op
+	changed, err = store.Ingest(context.Background(), path)
+	assert.NoError(t, err)
+	assert.False(t, changed)
+	assert.Equal(t, 3, countRows(t, store, "SELECT COUNT(*) FROM matches"))
+
+	// a changed log replaces what was stored for it
+	assert.NoError(t, os.WriteFile(path, content[:len(content)/2], 0o644))
+	changed, err = store.Ingest(context.Background(), path)
+	assert.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, 1, countRows(t, store, "SELECT COUNT(*) FROM logs"))
+	assert.Equal(t, 1, countRows(t, store, "SELECT COUNT(*) FROM matches"))
+	assert.Equal(t, 0, countRows(t, store, "SELECT COUNT(*) FROM kills"))
+}
+
+func TestIngestOverlapping(t *testing.T) {
+	content, err := os.ReadFile("../../assets/test.log")
+	assert.NoError(t, err)
+
+	tmpdir := t.TempDir()
+	copied := filepath.Join(tmpdir, "copy.log")
+	rotated := filepath.Join(tmpdir, "rotated.log")
+	assert.NoError(t, os.WriteFile(copied, content, 0o644))
+	// the last two matches of test.log, from the line of the second InitGame
+	first := bytes.Index(content, []byte("InitGame:"))
+	second := first + 1 + bytes.Index(content[first+1:], []byte("InitGame:"))
+	second = bytes.LastIndexByte(content[:second], '\n') + 1
+	assert.NoError(t, os.WriteFile(rotated, content[second:], 0o644))
+
+	store, err := Open(filepath.Join(tmpdir, "quake.db"))
+	assert.NoError(t, err)
+	defer store.Close()
+
+	changed, err := store.Ingest(context.Background(), "../../assets/test.log")
+	assert.NoError(t, err)
+	assert.True(t, changed)
+
+	// the same content from another path is already stored
+	changed, err = store.Ingest(context.Background(), copied)
+	assert.NoError(t, err)
+	assert.False(t, changed)
+
+	// the matches of an overlapping log are already stored too
+	changed, err = store.Ingest(context.Background(), rotated)
+	assert.NoError(t, err)
+	assert.True(t, changed)
+
+	assert.Equal(t, 2, countRows(t, store, "SELECT COUNT(*) FROM logs"))
+	assert.Equal(t, 3, countRows(t, store, "SELECT COUNT(*) FROM matches"))
+	assert.Equal(t, 3, countRows(t, store, "SELECT COUNT(DISTINCT fingerprint) FROM matches"))
+	assert.Equal(t, 21, countRows(t, store, "SELECT COUNT(*) FROM kills"))
+}
+
+func TestIngestReplacedOverlapping(t *testing.T) {
+	content, err := os.ReadFile("../../assets/test.log")
+	assert.NoError(t, err)
+
+	tmpdir := t.TempDir()
+	full := filepath.Join(tmpdir, "full.log")
+	rotated := filepath.Join(tmpdir, "rotated.log")
+	assert.NoError(t, os.WriteFile(full, content, 0o644))
+	first := bytes.Index(content, []byte("InitGame:"))
+	second := first + 1 + bytes.Index(content[first+1:], []byte("InitGame:"))
+	second = bytes.LastIndexByte(content[:second], '\n') + 1
+	assert.NoError(t, os.WriteFile(rotated, content[second:], 0o644))
+
+	store, err := Open(filepath.Join(tmpdir, "quake.db"))
+	assert.NoError(t, err)
+	defer store.Close()
+
+	for _, path := range []string{full, rotated} {
+		_, err := store.Ingest(context.Background(), path)
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, 3, countRows(t, store, "SELECT COUNT(*) FROM matches"))
+	assert.Equal(t, 2, countRows(t, store, "SELECT COUNT(*) FROM log_matches l JOIN logs g ON g.id = l.log_id WHERE g.path = ?", rotated))
+
+	// the full log changes, only keeping its first match, unfinished now
+	assert.NoError(t, os.WriteFile(full, content[:second], 0o644))
+	changed, err := store.Ingest(context.Background(), full)
+	assert.NoError(t, err)
+	assert.True(t, changed)
+
+	assert.Equal(t, 2, countRows(t, store, "SELECT COUNT(*) FROM matches"), "The matches of the rotated log are kept")
+	assert.Equal(t, 21, countRows(t, store, "SELECT COUNT(*) FROM kills"))
+	assert.Equal(t, 0, countRows(t, store, "SELECT COUNT(*) FROM log_matches l JOIN logs g ON g.id = l.log_id WHERE g.path = ?", full))
+	assert.Equal(t, 0, countRows(t, store, "SELECT COUNT(*) FROM match_players WHERE match_id NOT IN (SELECT id FROM matches)"),
+		"The match found in no stored log is removed")
+}
+
+func TestIngestOffsets(t *testing.T) {
+	store, err := Open(filepath.Join(t.TempDir(), "quake.db"))
+	assert.NoError(t, err)
+	defer store.Close()
+
+	_, err = store.Ingest(context.Background(), "../../assets/test.log")
+	assert.NoError(t, err)
+
+	var ingestedAt string
+	var duration int
+	assert.NoError(t, store.db.QueryRow("SELECT ingested_at, duration_seconds FROM matches m JOIN log_matches l ON l.match_id = m.id WHERE l.game = 'game_2'").
+		Scan(&ingestedAt, &duration))
+	_, err = time.Parse(time.RFC3339, ingestedAt)
+	assert.NoError(t, err)
+	assert.Positive(t, duration)
+
+	var offsets []int
+	rows, err := store.db.Query(`SELECT k.offset_seconds FROM kills k JOIN log_matches l ON l.match_id = k.match_id
+		WHERE l.game = 'game_2' ORDER BY k.id LIMIT 3`)
+	assert.NoError(t, err)
+	defer rows.Close()
+	for rows.Next() {
+		var offset int
+		assert.NoError(t, rows.Scan(&offset))
+		offsets = append(offsets, offset)
+	}
+	assert.Equal(t, []int{10, 12, 14}, offsets)
+
+	var connected int
+	var disconnected sql.NullInt64
+	assert.NoError(t, store.db.QueryRow(`SELECT s.connected_offset_seconds, s.disconnected_offset_seconds
+		FROM player_sessions s JOIN log_matches l ON l.match_id = s.match_id WHERE l.game = 'game_2' AND s.client_id = 3`).
+		Scan(&connected, &disconnected))
+	assert.Equal(t, 3, connected)
+	assert.Equal(t, sql.NullInt64{Int64: int64(duration), Valid: true}, disconnected)
+}
+
+func TestIngestNonExistentFile(t *testing.T) {
+	store, err := Open(filepath.Join(t.TempDir(), "quake.db"))
+	assert.NoError(t, err)
+	defer store.Close()
+
+	_, err = store.Ingest(context.Background(), "/nonexistent/file.log")
+
+	assert.ErrorContains(t, err, "failed to open quake log file")
+}
+
+func TestIngestCancelled(t *testing.T) {
+	store, err := Open(filepath.Join(t.TempDir(), "quake.db"))
+	assert.NoError(t, err)
+	defer store.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	changed, err := store.Ingest(ctx, "../../assets/test.log")
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.False(t, changed)
+	assert.Equal(t, 0, countRows(t, store, "SELECT COUNT(*) FROM logs"))
+}
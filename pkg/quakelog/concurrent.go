@@ -0,0 +1,182 @@
+package quakelog
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// ParseConcurrently parses the log in r, which is size bytes long, like Parse
+// but using up to workers goroutines. The log is first scanned for its
+// InitGame lines, then each match, from its InitGame to the next one, is
+// parsed on its own and the reports are put back in the order of the log,
+// with the same numbering Parse gives them. An InitGame spliced to the end of
+// another line stays with the match it cuts off, as in Parse.
+//
+// Handlers registered with OnMatchEnd, OnEvent and OnDiagnostic are called
+// from several goroutines and not in the order of the log. In Strict mode the
+// first anomaly of the log is returned. WithState is not supported, nor are
+// the orphan policies other than ORPHAN_DROP, as the events written between
+// two matches would have to cross from one segment to the next.
+func (p *Parser) ParseConcurrently(ctx context.Context, r io.ReaderAt, size int64, workers int) (GameReport, error) {
+	game := newGameState(p.options)
+	if game.state != nil {
+		return nil, errors.New("WithState cannot be used when parsing concurrently")
+	}
+	if game.orphanPolicy == ORPHAN_BUFFER || game.orphanPolicy == ORPHAN_ATTACH {
+		return nil, fmt.Errorf("orphan policy %s cannot be used when parsing concurrently", game.orphanPolicy)
+	}
+
+	offsets, lines, started, err := game.initGameOffsets(ctx, io.NewSectionReader(r, 0, size))
+	if err != nil {
+		return nil, err
+	}
+
+	// the first segment holds the lines before the first InitGame, and each of
+	// the others starts at an InitGame and ends right before the next one
+	bounds := append(append([]int64{0}, offsets...), size)
+	lines = append([]int{0}, lines...)
+	started = append([]int{0}, started...)
+	reports := make([]GameReport, len(bounds)-1)
+	errs := make([]error, len(reports))
+	segments := make(chan int)
+
+	var wg sync.WaitGroup
+	for range max(1, min(workers, len(reports))) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range segments {
+				section := io.NewSectionReader(r, bounds[i], bounds[i+1]-bounds[i])
+				next := ""
+				if i < len(reports)-1 {
+					next = lineAt(r, bounds[i+1], size)
+				}
+				reports[i], errs[i] = p.parseSegment(ctx, section, started[i], lines[i], next)
+			}
+		}()
+	}
+
+	for i := range reports {
+		if ctx.Err() != nil {
+			break
+		}
+		segments <- i
+	}
+	close(segments)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	// the first failure of the log, as the sequential parsing would return
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	report := make(GameReport, 0)
+	for _, segment := range reports {
+		report = append(report, segment...)
+	}
+
+	return report, nil
+}
+
+// parseSegment parses a part of the log after the given number of started
+// matches and lines. Unless it is the last part, a match still in progress at
+// its end is closed, as next, the InitGame line starting the next part, would
+// do.
+func (p *Parser) parseSegment(ctx context.Context, r io.Reader, started, lines int, next string) (GameReport, error) {
+	game := newGameState(p.options)
+	game.totalGames = started
+	game.lineNumber = lines
+
+	scanner := bufio.NewScanner(r)
+	for game.err == nil && scanner.Scan() {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		game.parseLine(scanner.Text())
+	}
+
+	if game.err != nil {
+		return nil, game.err
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading log: %w", err)
+	}
+
+	if next != "" && game.gameStarted {
+		game.lineNumber++
+		game.line = next
+		game.time = parseTime(next)
+		game.closeUnfinished()
+	}
+
+	return game.gameReport, nil
+}
+
+// lineAt returns the line of r starting at offset.
+func lineAt(r io.ReaderAt, offset, size int64) string {
+	scanner := bufio.NewScanner(io.NewSectionReader(r, offset, size-offset))
+	scanner.Scan()
+	return scanner.Text()
+}
+
+// initGameOffsets returns the byte offset of every InitGame line in r, with
+// the number of lines and of matches started before it. An InitGame spliced
+// to the end of another line starts a match, but the log is not split there:
+// the line is still part of the previous match, which it damages.
+func (game *gameState) initGameOffsets(ctx context.Context, r io.Reader) ([]int64, []int, []int, error) {
+	offsets := make([]int64, 0)
+	lines := make([]int, 0)
+	started := make([]int, 0)
+	count, matches := 0, 0
+	initGame := RegexPatterns[INIT_GAME]
+	marker := []byte("InitGame:")
+
+	var offset, lineStart int64
+	scanner := bufio.NewScanner(r)
+	scanner.Split(func(data []byte, atEOF bool) (int, []byte, error) {
+		advance, token, err := bufio.ScanLines(data, atEOF)
+		lineStart = offset
+		offset += int64(advance)
+		return advance, token, err
+	})
+
+	for ; scanner.Scan(); count++ {
+		// the cheap check skips the regular expression on most lines
+		if !bytes.Contains(scanner.Bytes(), marker) || !initGame.Match(scanner.Bytes()) {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, nil, nil, err
+		}
+
+		entry, spliced := scanner.Text(), false
+		for next, ok := game.spliced(entry); ok; next, ok = game.spliced(entry) {
+			entry, spliced = next, true
+		}
+		if name := LineEntry.FindStringSubmatch(entry); name == nil || name[2] != INIT_GAME {
+			continue
+		}
+		if !spliced {
+			offsets = append(offsets, lineStart)
+			lines = append(lines, count)
+			started = append(started, matches)
+		}
+		matches++
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, nil, nil, fmt.Errorf("error reading log: %w", err)
+	}
+
+	return offsets, lines, started, nil
+}
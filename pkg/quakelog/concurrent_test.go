@@ -0,0 +1,138 @@
+package quakelog
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseConcurrentlyMatchesParse(t *testing.T) {
+	content, err := os.ReadFile("../../assets/qgames.log")
+	assert.NoError(t, err)
+
+	expected, err := New().Parse(strings.NewReader(string(content)))
+	assert.NoError(t, err)
+
+	for _, workers := range []int{0, 1, 3, 8, 64} {
+		report, err := New().ParseConcurrently(context.Background(), strings.NewReader(string(content)), int64(len(content)), workers)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, report, "workers: %d", workers)
+	}
+}
+
+func TestParseConcurrently(t *testing.T) {
+	tests := []struct {
+		name string
+		log  string
+	}{
+		{
+			name: "Empty log",
+			log:  "",
+		},
+		{
+			name: "No InitGame",
+			log:  "  0:01 ClientConnect: 2\n  0:01 ClientUserinfoChanged: 2 n\\Player1\\t\\0\n",
+		},
+		{
+			name: "Lines before the first InitGame",
+			log: "  0:00 ------------------------------------------------------------\n" +
+				"  0:01 ClientUserinfoChanged: 2 n\\Player1\\t\\0\n" +
+				"  0:02 InitGame: \\mapname\\q3dm17\n" +
+				"  0:03 Kill: 1022 2 22: <world> killed Player1 by MOD_TRIGGER_HURT\n" +
+				"  0:04 ShutdownGame:\n",
+		},
+		{
+			name: "ShutdownGame before any InitGame",
+			log:  "  0:01 ShutdownGame:\n  0:02 InitGame: \\mapname\\q3dm17\n  0:03 ShutdownGame:\n",
+		},
+		{
+			name: "Match closed by the next InitGame",
+			log: "  0:00 InitGame: \\mapname\\q3dm17\n" +
+				"  0:01 ClientUserinfoChanged: 2 n\\Player1\\t\\0\n" +
+				"  0:02 Kill: 1022 2 22: <world> killed Player1 by MOD_TRIGGER_HURT\n" +
+				"  0:03 InitGame: \\mapname\\q3dm6\n" +
+				"  0:04 ClientUserinfoChanged: 3 n\\Player2\\t\\0\n" +
+				"  0:05 Kill: 3 3 7: Player2 killed Player2 by MOD_ROCKET_SPLASH\n" +
+				"  0:06 ShutdownGame:\n",
+		},
+		{
+			name: "InitGame spliced to a line",
+			log: "  0:00 InitGame: \\mapname\\q3dm17\n" +
+				"  0:01 ClientUserinfoChanged: 2 n\\Player1\\t\\0\n" +
+				"  0:02 Kill: 1022 2 22: <world> kil  0:00 InitGame: \\mapname\\q3dm6\n" +
+				"  0:01 ClientUserinfoChanged: 3 n\\Player2\\t\\0\n" +
+				"  0:02 Kill: 3 3 7: Player2 killed Player2 by MOD_ROCKET_SPLASH\n" +
+				"  0:03 InitGame: \\mapname\\q3dm17\n" +
+				"  0:04 ShutdownGame:\n",
+		},
+		{
+			name: "Last match in progress, CRLF and no final newline",
+			log: "  0:00 InitGame: \\mapname\\q3dm17\r\n" +
+				"  0:01 ShutdownGame:\r\n" +
+				"  0:02 InitGame: \\mapname\\q3dm6\r\n" +
+				"  0:03 ClientUserinfoChanged: 2 n\\Player1\\t\\0",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			expected, err := New().Parse(strings.NewReader(tc.log))
+			assert.NoError(t, err)
+
+			report, err := New().ParseConcurrently(context.Background(), strings.NewReader(tc.log), int64(len(tc.log)), 4)
+
+			assert.NoError(t, err)
+			assert.Equal(t, expected, report)
+		})
+	}
+}
+
+func TestParseConcurrentlyErrors(t *testing.T) {
+	log := "  0:00 InitGame: \\mapname\\q3dm17\n  0:01 ShutdownGame:\n"
+
+	t.Run("WithState", func(t *testing.T) {
+		_, err := New(WithState(&State{})).ParseConcurrently(context.Background(), strings.NewReader(log), int64(len(log)), 2)
+
+		assert.EqualError(t, err, "WithState cannot be used when parsing concurrently")
+	})
+
+	t.Run("Cancelled while parsing a segment", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		log := "  0:00 InitGame: \\mapname\\q3dm17\n" +
+			"  0:01 ClientConnect: 2\n" +
+			"  0:02 ClientConnect: 3\n" +
+			"  0:03 ClientConnect: 4\n"
+		events := 0
+		parser := New(OnEvent(func(Event, Scoreboard) {
+			events++
+			cancel()
+		}))
+
+		_, err := parser.ParseConcurrently(ctx, strings.NewReader(log), int64(len(log)), 1)
+
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, 1, events, "The segment stops at the first line after the cancellation")
+	})
+
+	t.Run("Cancelled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := New().ParseConcurrently(ctx, strings.NewReader(log), int64(len(log)), 2)
+
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("Line too long", func(t *testing.T) {
+		long := log + strings.Repeat("x", 100*1024) + "\n"
+
+		_, err := New().ParseConcurrently(context.Background(), strings.NewReader(long), int64(len(long)), 2)
+
+		assert.ErrorContains(t, err, "error reading log")
+	})
+}
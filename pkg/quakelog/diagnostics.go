@@ -0,0 +1,200 @@
+package quakelog
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Kinds of the anomalies found in a log.
+const (
+	DIAGNOSTIC_UNKNOWN_LINE    = "unknown_line"
+	DIAGNOSTIC_MALFORMED_LINE  = "malformed_line"
+	DIAGNOSTIC_MEANS_MISSING   = "means_missing"
+	DIAGNOSTIC_MEANS_TRUNCATED = "means_truncated"
+	DIAGNOSTIC_MEANS_MISMATCH  = "means_mismatch"
+
+	DIAGNOSTIC_SPLICED_LINE     = "spliced_line"
+	DIAGNOSTIC_CLOCK_JUMP       = "clock_jump"
+	DIAGNOSTIC_UNKNOWN_CLIENT   = "unknown_client"
+	DIAGNOSTIC_MISSING_SHUTDOWN = "missing_shutdown"
+
+	DIAGNOSTIC_ORPHAN_EVENT = "orphan_event"
+)
+
+// SECTION_DIAGNOSTICS is the section of the reports listing the anomalies of
+// the match, added with ReportDiagnostics.
+const SECTION_DIAGNOSTICS = "diagnostics"
+
+// Diagnostic is an anomaly found in a log line: Kind is one of the
+// DIAGNOSTIC_* constants, Reason explains it and Text is the line as written
+// in the log. Line counts the lines from the start of the parsing, which is
+// the start of the log unless it was resumed through WithState.
+type Diagnostic struct {
+	Line   int    `json:"line"`
+	Game   string `json:"game,omitempty"`
+	Time   string `json:"time,omitempty"`
+	Kind   string `json:"kind"`
+	Reason string `json:"reason"`
+	Text   string `json:"text"`
+}
+
+// DiagnosticError is the anomaly that stopped a parsing in strict mode.
+type DiagnosticError struct {
+	Diagnostic Diagnostic
+}
+
+func (e *DiagnosticError) Error() string {
+	return fmt.Sprintf("line %d: %s: %q", e.Diagnostic.Line, e.Diagnostic.Reason, e.Diagnostic.Text)
+}
+
+// Diagnostics collects the anomalies of a log when its Add method is
+// registered with OnDiagnostic. It is safe for concurrent use.
+type Diagnostics struct {
+	mu          sync.Mutex
+	diagnostics []Diagnostic
+}
+
+// Add records diagnostic.
+func (d *Diagnostics) Add(diagnostic Diagnostic) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.diagnostics = append(d.diagnostics, diagnostic)
+}
+
+// List returns the recorded anomalies, in the order they were found.
+func (d *Diagnostics) List() []Diagnostic {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return append([]Diagnostic(nil), d.diagnostics...)
+}
+
+// Summary returns the number of recorded anomalies of each kind.
+func (d *Diagnostics) Summary() map[string]int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	summary := make(map[string]int)
+	for _, diagnostic := range d.diagnostics {
+		summary[diagnostic.Kind]++
+	}
+
+	return summary
+}
+
+// Strict stops the parsing at the first anomaly, which is returned as a
+// *DiagnosticError instead of the report.
+func Strict() Option {
+	return func(game *gameState) {
+		game.strict = true
+	}
+}
+
+// ReportDiagnostics adds the anomalies found in each match to its report,
+// under SECTION_DIAGNOSTICS, so they are kept with the parse results. The
+// anomalies of the lines outside a match are only sent to OnDiagnostic.
+func ReportDiagnostics() Option {
+	return func(game *gameState) {
+		game.reportDiagnostics = true
+	}
+}
+
+// IgnoreEntries adds entries, named as in the log before their colon, to the
+// ones the reports do not use, so lines written by mods are not reported as
+// unknown.
+func IgnoreEntries(entries ...string) Option {
+	return func(game *gameState) {
+		ignored := make(map[string]bool, len(game.ignored)+len(entries))
+		for entry := range game.ignored {
+			ignored[entry] = true
+		}
+		for _, entry := range entries {
+			ignored[entry] = true
+		}
+		game.ignored = ignored
+	}
+}
+
+// ignoredEntries are the entries of baseq3 and missionpack logs the reports
+// do not use.
+var ignoredEntries = map[string]bool{
+	"ClientBegin": true,
+	"Item":        true,
+	"Exit":        true,
+	"score":       true,
+	"red":         true,
+	"say":         true,
+	"sayteam":     true,
+	"tell":        true,
+	"Warmup":      true,
+	"CTF":         true,
+	"Award":       true,
+	"Challenge":   true,
+	"PlayerScore": true,
+}
+
+// checkEntry reports a line none of the RegexPatterns matched, unless it is
+// blank, a separator or an entry the reports do not use.
+func (game *gameState) checkEntry(line string) {
+	if strings.TrimSpace(line) == "" {
+		return
+	}
+
+	matches := LineEntry.FindStringSubmatch(line)
+	switch {
+	case matches == nil:
+		game.diagnose(DIAGNOSTIC_MALFORMED_LINE, "line does not start with the time of an entry")
+	case matches[2] == "" || game.ignored[matches[2]]:
+	case RegexPatterns[matches[2]] != nil:
+		game.diagnose(DIAGNOSTIC_MALFORMED_LINE, "%s line does not match its format", matches[2])
+	default:
+		game.diagnose(DIAGNOSTIC_UNKNOWN_LINE, "unknown entry %s", matches[2])
+	}
+}
+
+// atoi converts the numeric fields of the line being parsed, reporting it as
+// malformed when one of them does not fit an int.
+func (game *gameState) atoi(fields ...string) ([]int, bool) {
+	values := make([]int, len(fields))
+	for i, field := range fields {
+		value, err := strconv.Atoi(field)
+		if err != nil {
+			game.diagnose(DIAGNOSTIC_MALFORMED_LINE, "invalid number %s", field)
+			return nil, false
+		}
+		values[i] = value
+	}
+
+	return values, true
+}
+
+// diagnose reports an anomaly of the line being parsed, which stops the
+// parsing in strict mode.
+func (game *gameState) diagnose(kind, format string, args ...any) {
+	diagnostic := Diagnostic{
+		Line:   game.lineNumber,
+		Time:   game.time,
+		Kind:   kind,
+		Reason: fmt.Sprintf(format, args...),
+		Text:   game.line,
+	}
+	if game.gameStarted {
+		diagnostic.Game = game.gameName()
+	}
+
+	if game.gameStarted && integrityKinds[kind] {
+		game.addIssue(diagnostic)
+	}
+	if game.gameStarted && game.reportDiagnostics {
+		game.diagnostics = append(game.diagnostics, diagnostic)
+	}
+	if game.onDiagnostic != nil {
+		game.onDiagnostic(diagnostic)
+	}
+	if game.strict && game.err == nil {
+		game.err = &DiagnosticError{Diagnostic: diagnostic}
+	}
+}
@@ -0,0 +1,214 @@
+package quakelog
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiagnostics(t *testing.T) {
+	tests := []struct {
+		name     string
+		line     string
+		options  []Option
+		kind     string
+		reason   string
+		expected int
+	}{
+		{
+			name: "Separator",
+			line: "  0:01 ------------------------------------------------------------",
+		},
+		{
+			name: "Blank line",
+			line: "   ",
+		},
+		{
+			name: "Entry not used by the reports",
+			line: "  0:01 Item: 2 weapon_rocketlauncher",
+		},
+		{
+			name:   "Unknown entry",
+			line:   "  0:01 Flag: 2 0: team_CTF_blueflag",
+			kind:   DIAGNOSTIC_UNKNOWN_LINE,
+			reason: "unknown entry Flag",
+		},
+		{
+			name:    "Ignored unknown entry",
+			line:    "  0:01 Flag: 2 0: team_CTF_blueflag",
+			options: []Option{IgnoreEntries("Flag")},
+		},
+		{
+			name:   "Known entry not matching its format",
+			line:   "  0:01 ClientConnect: two",
+			kind:   DIAGNOSTIC_MALFORMED_LINE,
+			reason: "ClientConnect line does not match its format",
+		},
+		{
+			name:   "Line without time",
+			line:   "garbled line",
+			kind:   DIAGNOSTIC_MALFORMED_LINE,
+			reason: "line does not start with the time of an entry",
+		},
+		{
+			name:     "Number out of range",
+			line:     "  0:01 Kill: 99999999999999999999 3 10: Player1 killed Player2 by MOD_RAILGUN",
+			kind:     DIAGNOSTIC_MALFORMED_LINE,
+			reason:   "invalid number 99999999999999999999",
+			expected: -1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			log := "  0:00 InitGame: \\mapname\\q3dm17\n" +
+				tc.line + "\n" +
+				"  0:01 ClientUserinfoChanged: 2 n\\Player1\\t\\0\n" +
+				"  0:02 Kill: 1022 2 22: <world> killed Player1 by MOD_TRIGGER_HURT\n" +
+				"  0:03 ShutdownGame:\n"
+
+			diagnostics := &Diagnostics{}
+			report, err := New(append(tc.options, OnDiagnostic(diagnostics.Add))...).Parse(strings.NewReader(log))
+
+			assert.NoError(t, err)
+			assert.Equal(t, 1, report[0]["game_1"].TotalKills, "A line with an invalid number is skipped")
+			if tc.kind == "" {
+				assert.Empty(t, diagnostics.List())
+				return
+			}
+
+			assert.Equal(t, []Diagnostic{{
+				Line:   2,
+				Game:   "game_1",
+				Time:   parseTime(tc.line),
+				Kind:   tc.kind,
+				Reason: tc.reason,
+				Text:   tc.line,
+			}}, diagnostics.List())
+			assert.Equal(t, map[string]int{tc.kind: 1}, diagnostics.Summary())
+		})
+	}
+}
+
+func TestDiagnosticsQgames(t *testing.T) {
+	content, err := os.ReadFile("../../assets/qgames.log")
+	assert.NoError(t, err)
+
+	diagnostics := &Diagnostics{}
+	_, err = New(OnDiagnostic(diagnostics.Add)).Parse(strings.NewReader(string(content)))
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{DIAGNOSTIC_SPLICED_LINE: 1, DIAGNOSTIC_MISSING_SHUTDOWN: 1}, diagnostics.Summary())
+	assert.Equal(t, 97, diagnostics.List()[0].Line, "The line cut off by the next one is reported")
+	assert.Equal(t, 98, diagnostics.List()[1].Line, "The match is closed by the next InitGame")
+
+	concurrent := &Diagnostics{}
+	_, err = New(OnDiagnostic(concurrent.Add)).ParseConcurrently(context.Background(), strings.NewReader(string(content)), int64(len(content)), 4)
+
+	assert.NoError(t, err)
+	assert.Equal(t, diagnostics.List(), concurrent.List(), "The lines are numbered from the start of the log")
+}
+
+func TestReportDiagnostics(t *testing.T) {
+	log := "  0:00 Flag: 2 0: team_CTF_blueflag\n" +
+		"  0:01 InitGame: \\mapname\\q3dm17\n" +
+		"  0:02 Flag: 2 0: team_CTF_blueflag\n" +
+		"  0:03 ShutdownGame:\n" +
+		"  0:04 InitGame: \\mapname\\q3dm6\n" +
+		"  0:05 ShutdownGame:\n"
+
+	report, err := New(ReportDiagnostics()).Parse(strings.NewReader(log))
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{SECTION_DIAGNOSTICS: []Diagnostic{{
+		Line:   3,
+		Game:   "game_1",
+		Time:   "0:02",
+		Kind:   DIAGNOSTIC_UNKNOWN_LINE,
+		Reason: "unknown entry Flag",
+		Text:   "  0:02 Flag: 2 0: team_CTF_blueflag",
+	}}}, report[0]["game_1"].Sections, "The anomalies outside a match are not in the report")
+	assert.Nil(t, report[1]["game_2"].Sections, "A match without anomalies has no section")
+
+	resumed := &State{}
+	first, err := New(ReportDiagnostics(), WithState(resumed)).Parse(strings.NewReader(log[:strings.Index(log, "  0:03")]))
+	assert.NoError(t, err)
+	second, err := New(ReportDiagnostics(), WithState(resumed)).Parse(strings.NewReader(log[strings.Index(log, "  0:03"):]))
+	assert.NoError(t, err)
+	assert.Equal(t, report, append(first, second...), "The anomalies of a match in progress are kept in the State")
+
+	concurrent, err := New(ReportDiagnostics()).ParseConcurrently(context.Background(), strings.NewReader(log), int64(len(log)), 2)
+	assert.NoError(t, err)
+	assert.Equal(t, report, concurrent)
+}
+
+func TestStrict(t *testing.T) {
+	log := "  0:00 InitGame: \\mapname\\q3dm17\n" +
+		"  0:01 ShutdownGame:\n" +
+		"  0:02 InitGame: \\mapname\\q3dm6\n" +
+		"  0:03 Kill: 2 3 10: Player1 killed Player2 by MOD_RAIL\n" +
+		"  0:04 Flag: 2 0: team_CTF_blueflag\n" +
+		"  0:05 ShutdownGame:\n"
+	expected := Diagnostic{
+		Line:   4,
+		Game:   "game_2",
+		Time:   "0:03",
+		Kind:   DIAGNOSTIC_MEANS_TRUNCATED,
+		Reason: "means of death MOD_RAIL cut off, 10 is MOD_RAILGUN",
+		Text:   "  0:03 Kill: 2 3 10: Player1 killed Player2 by MOD_RAIL",
+	}
+
+	t.Run("Parse", func(t *testing.T) {
+		report, err := New(Strict()).Parse(strings.NewReader(log))
+
+		var diagnosticErr *DiagnosticError
+		assert.True(t, errors.As(err, &diagnosticErr))
+		assert.Equal(t, expected, diagnosticErr.Diagnostic)
+		assert.EqualError(t, err, `line 4: means of death MOD_RAIL cut off, 10 is MOD_RAILGUN: "  0:03 Kill: 2 3 10: Player1 killed Player2 by MOD_RAIL"`)
+		assert.Nil(t, report)
+	})
+
+	t.Run("ParseLines", func(t *testing.T) {
+		lines := make(chan string)
+		gameReport := make(chan GameReport)
+		errChan := make(chan error)
+		go func() {
+			defer close(lines)
+			for _, line := range strings.Split(log, "\n") {
+				select {
+				case lines <- line:
+				case <-gameReport:
+					return
+				}
+			}
+		}()
+		go ParseLines(context.Background(), lines, gameReport, errChan, Strict())
+
+		err := <-errChan
+		_, ok := <-gameReport
+
+		var diagnosticErr *DiagnosticError
+		assert.True(t, errors.As(err, &diagnosticErr))
+		assert.Equal(t, expected, diagnosticErr.Diagnostic)
+		assert.False(t, ok, "No report is sent")
+	})
+
+	t.Run("ParseConcurrently", func(t *testing.T) {
+		_, err := New(Strict()).ParseConcurrently(context.Background(), strings.NewReader(log), int64(len(log)), 4)
+
+		var diagnosticErr *DiagnosticError
+		assert.True(t, errors.As(err, &diagnosticErr))
+		assert.Equal(t, expected, diagnosticErr.Diagnostic, "The first anomaly of the log is returned")
+	})
+
+	t.Run("Without anomalies", func(t *testing.T) {
+		report, err := New(Strict()).Parse(strings.NewReader(testLog))
+
+		assert.NoError(t, err)
+		assert.NotEmpty(t, report)
+	})
+}
@@ -0,0 +1,36 @@
+// Package quakelog parses Quake 3 Arena server logs (games.log) into
+// per-match reports with the kills of each player and the kills grouped by
+// means of death, and decodes the log lines into events.
+//
+// The simplest entry point is a Parser:
+//
+//	report, err := quakelog.New().Parse(file)
+//
+// Options customize the parsing: OnMatchEnd and OnEvent stream the matches
+// and the decoded events as they happen, WithHandler adds custom statistics
+// to the match reports, WithMeansRegistry describes the means of death of mods
+// and other engines, and WithState resumes the parsing of a growing log.
+// ParseLines offers the same parsing over a channel of lines, for pipelines
+// that read the log concurrently and may be cancelled through a context,
+// while Parser.ParseConcurrently parses the matches of a large log on several
+// goroutines.
+//
+// By default scoring follows the rules of the original challenge: a kill adds
+// one point to the killer, while a death caused by <world> or a suicide
+// removes one point from the victim. WithScoringRules changes them. Players
+// are identified by their client ID, so the reports list them as
+// "name (ID n)", sorted by client ID unless WithPlayerOrder sorts them by
+// name or score.
+//
+// Lines that are not understood are skipped. OnDiagnostic reports them, with
+// their line number and the reason, and Strict makes the parsing fail on the
+// first one instead. The matches damaged by a server crash, with lines cut
+// off, a clock going back, kills of unknown clients or no ShutdownGame, carry
+// their Integrity in the report. The events written outside a match are
+// dropped unless WithOrphanPolicy buffers them or attaches them to the next
+// match.
+//
+// Merge combines the reports of several logs into a season, dropping the
+// copies of the matches of overlapping logs identified by WithFingerprints,
+// and GameReport.Totals adds up its kills.
+package quakelog
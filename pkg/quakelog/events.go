@@ -0,0 +1,121 @@
+package quakelog
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Decoded event types
+const (
+	EVENT_MATCH_START = "match_start"
+	EVENT_JOIN        = "join"
+	EVENT_PLAYER_INFO = "player_info"
+	EVENT_KILL        = "kill"
+	EVENT_LEAVE       = "leave"
+	EVENT_MATCH_END   = "match_end"
+)
+
+// Event is a decoded log line. Only the field matching its type is set:
+// Player for joins, leaves and user info changes, Kill for kills and Match
+// for the report of a match that ended.
+type Event struct {
+	Type   string       `json:"type"`
+	Time   string       `json:"time"`
+	Game   string       `json:"game"`
+	Player *PlayerEvent `json:"player,omitempty"`
+	Kill   *KillEvent   `json:"kill,omitempty"`
+	Match  *MatchReport `json:"match,omitempty"`
+}
+
+// PlayerEvent identifies the player of a join, leave or user info event. The
+// name of a joining player is only known if they were seen before in the
+// match.
+type PlayerEvent struct {
+	ID   int    `json:"id"`
+	Name string `json:"name,omitempty"`
+}
+
+// KillEvent is a kill as written in the log, where the killer of deaths not
+// caused by a player is WORLD. Category and Label describe the means of death
+// when the MeansRegistry knows it, and TeamKill is set when the killer and
+// the victim are on the same team of a team game.
+type KillEvent struct {
+	KillerID int    `json:"killer_id"`
+	Killer   string `json:"killer"`
+	VictimID int    `json:"victim_id"`
+	Victim   string `json:"victim"`
+	Method   string `json:"method"`
+	MethodID int    `json:"method_id"`
+	Category string `json:"category,omitempty"`
+	Label    string `json:"label,omitempty"`
+	TeamKill bool   `json:"team_kill,omitempty"`
+}
+
+// Scoreboard is the current state of the match, with the players sorted by
+// kills.
+type Scoreboard struct {
+	Game       string            `json:"game"`
+	Map        string            `json:"map,omitempty"`
+	InProgress bool              `json:"in_progress"`
+	TotalKills int               `json:"total_kills"`
+	Players    []ScoreboardEntry `json:"players"`
+}
+
+// ScoreboardEntry is the current score of a player.
+type ScoreboardEntry struct {
+	ID    int    `json:"id"`
+	Name  string `json:"name"`
+	Kills int    `json:"kills"`
+}
+
+func (game *gameState) emit(event Event) {
+	// events outside of a match are not part of any match report
+	var handlers []EventHandler
+	if game.gameStarted {
+		handlers = game.activeHandlers()
+	}
+	if game.onEvent == nil && len(handlers) == 0 {
+		return
+	}
+
+	event.Time = game.time
+	event.Game = game.gameName()
+	scoreboard := game.scoreboard()
+
+	for _, handler := range handlers {
+		handler.HandleEvent(event, scoreboard)
+	}
+	if game.onEvent != nil {
+		game.onEvent(event, scoreboard)
+	}
+}
+
+func (game *gameState) gameName() string {
+	if game.inUnscoped {
+		return UNSCOPED
+	}
+	return fmt.Sprintf("game_%d", game.totalGames)
+}
+
+func (game *gameState) scoreboard() Scoreboard {
+	scoreboard := Scoreboard{
+		Game:       game.gameName(),
+		Map:        game.matchReport.Map,
+		InProgress: game.gameStarted,
+		TotalKills: game.matchReport.TotalKills,
+		Players:    make([]ScoreboardEntry, 0, len(game.players)),
+	}
+
+	for ID, player := range game.players {
+		scoreboard.Players = append(scoreboard.Players, ScoreboardEntry{ID: ID, Name: player.name, Kills: player.kills})
+	}
+
+	sort.Slice(scoreboard.Players, func(i, j int) bool {
+		if scoreboard.Players[i].Kills != scoreboard.Players[j].Kills {
+			return scoreboard.Players[i].Kills > scoreboard.Players[j].Kills
+		}
+		return scoreboard.Players[i].ID < scoreboard.Players[j].ID
+	})
+
+	return scoreboard
+}
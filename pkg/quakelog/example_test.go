@@ -0,0 +1,127 @@
+package quakelog_test
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/vhrboliveira/quake-log-parser-test/pkg/quakelog"
+)
+
+const exampleLog = `  0:00 InitGame: \mapname\q3dm17
+  0:01 ClientUserinfoChanged: 2 n\Isgalamido\t\0
+  0:01 ClientUserinfoChanged: 3 n\Mocinha\t\0
+  0:02 Kill: 2 3 10: Isgalamido killed Mocinha by MOD_RAILGUN
+  0:03 Kill: 1022 2 22: <world> killed Isgalamido by MOD_TRIGGER_HURT
+  0:04 ShutdownGame:
+`
+
+func ExampleParser_Parse() {
+	report, err := quakelog.New().Parse(strings.NewReader(exampleLog))
+	if err != nil {
+		panic(err)
+	}
+
+	match := report[0]["game_1"]
+	fmt.Println(match.Map, match.TotalKills)
+	fmt.Println(match.Kills["Isgalamido (ID 2)"], match.Kills["Mocinha (ID 3)"])
+	fmt.Println(match.KillsByMeans[quakelog.MOD_RAILGUN])
+	// Output:
+	// q3dm17 2
+	// 0 0
+	// 1
+}
+
+func ExampleParser_ParseEvents() {
+	events, err := quakelog.New().ParseEvents(strings.NewReader(exampleLog))
+	if err != nil {
+		panic(err)
+	}
+
+	for _, event := range events {
+		if event.Type == quakelog.EVENT_KILL {
+			fmt.Printf("%s %s killed %s by %s\n", event.Time, event.Kill.Killer, event.Kill.Victim, event.Kill.Method)
+		}
+	}
+	// Output:
+	// 0:02 Isgalamido killed Mocinha by MOD_RAILGUN
+	// 0:03 <world> killed Isgalamido by MOD_TRIGGER_HURT
+}
+
+func ExampleOnMatchEnd() {
+	parser := quakelog.New(quakelog.OnMatchEnd(func(match map[string]quakelog.MatchReport) {
+		for name, report := range match {
+			fmt.Println(name, "ended with", report.TotalKills, "kills")
+		}
+	}))
+
+	if _, err := parser.Parse(strings.NewReader(exampleLog)); err != nil {
+		panic(err)
+	}
+	// Output:
+	// game_1 ended with 2 kills
+}
+
+// lateRailFrags counts the railgun kills of each player in the last two
+// minutes of a match.
+type lateRailFrags struct {
+	kills []railKill
+	end   int
+}
+
+type railKill struct {
+	killer string
+	second int
+}
+
+func seconds(time string) int {
+	minutes, secs, _ := strings.Cut(time, ":")
+	m, _ := strconv.Atoi(minutes)
+	s, _ := strconv.Atoi(secs)
+	return m*60 + s
+}
+
+func (h *lateRailFrags) HandleEvent(event quakelog.Event, _ quakelog.Scoreboard) {
+	switch event.Type {
+	case quakelog.EVENT_KILL:
+		if event.Kill.Method == quakelog.MOD_RAILGUN && event.Kill.KillerID != event.Kill.VictimID {
+			h.kills = append(h.kills, railKill{killer: event.Kill.Killer, second: seconds(event.Time)})
+		}
+	case quakelog.EVENT_MATCH_END:
+		h.end = seconds(event.Time)
+	}
+}
+
+func (h *lateRailFrags) Section(quakelog.MatchReport) any {
+	frags := make(map[string]int)
+	for _, kill := range h.kills {
+		if kill.second >= h.end-120 {
+			frags[kill.killer]++
+		}
+	}
+	return frags
+}
+
+func ExampleWithHandler() {
+	log := `  0:00 InitGame: \mapname\q3dm17
+  0:01 ClientUserinfoChanged: 2 n\Isgalamido\t\0
+  0:01 ClientUserinfoChanged: 3 n\Mocinha\t\0
+  1:00 Kill: 2 3 10: Isgalamido killed Mocinha by MOD_RAILGUN
+  4:30 Kill: 3 2 10: Mocinha killed Isgalamido by MOD_RAILGUN
+  4:45 Kill: 2 3 6: Isgalamido killed Mocinha by MOD_ROCKET
+  5:00 ShutdownGame:
+`
+
+	parser := quakelog.New(quakelog.WithHandler("late_rail_frags", func() quakelog.EventHandler {
+		return &lateRailFrags{}
+	}))
+
+	report, err := parser.Parse(strings.NewReader(log))
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println(report[0]["game_1"].Sections["late_rail_frags"])
+	// Output:
+	// map[Mocinha:1]
+}
@@ -0,0 +1,39 @@
+package quakelog
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// fingerprintEvents is the number of events after InitGame hashed into the
+// Fingerprint of a match.
+const fingerprintEvents = 8
+
+// WithFingerprints sets the Fingerprint of the match reports: a hash of the
+// InitGame line, with its time and settings, and of the first events of the
+// match. The same match parsed from overlapping logs has the same
+// Fingerprint, which Merge uses to keep a single copy of it.
+func WithFingerprints() Option {
+	return func(game *gameState) {
+		game.fingerprints = true
+	}
+}
+
+// fingerprint hashes the line of the event just processed into the
+// Fingerprint of the match, chaining it to the previous one so the parsing can
+// be resumed from a State.
+func (game *gameState) fingerprint(eventType string) {
+	if !game.fingerprints || !game.gameStarted {
+		return
+	}
+
+	if eventType == INIT_GAME {
+		game.fingerprinted = 0
+	} else if game.fingerprinted++; game.fingerprinted > fingerprintEvents {
+		return
+	}
+
+	sum := sha256.Sum256([]byte(game.matchReport.Fingerprint + "\n" + strings.TrimSpace(game.line)))
+	game.matchReport.Fingerprint = hex.EncodeToString(sum[:8])
+}
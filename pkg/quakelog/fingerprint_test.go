@@ -0,0 +1,73 @@
+package quakelog
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fingerprints returns the fingerprints of the matches of report.
+func fingerprints(report GameReport) []string {
+	fingerprints := make([]string, 0, len(report))
+	for _, game := range report {
+		for _, match := range game {
+			fingerprints = append(fingerprints, match.Fingerprint)
+		}
+	}
+	return fingerprints
+}
+
+func TestWithFingerprints(t *testing.T) {
+	content, err := os.ReadFile("../../assets/qgames.log")
+	assert.NoError(t, err)
+	log := string(content)
+
+	report, err := New(WithFingerprints()).Parse(strings.NewReader(log))
+	assert.NoError(t, err)
+	expected := fingerprints(report)
+	assert.Len(t, expected, 21)
+
+	t.Run("Distinct matches", func(t *testing.T) {
+		seen := make(map[string]bool)
+		for _, fingerprint := range expected {
+			assert.Len(t, fingerprint, 16)
+			assert.False(t, seen[fingerprint], "Fingerprint %s is repeated", fingerprint)
+			seen[fingerprint] = true
+		}
+	})
+
+	t.Run("Overlapping log", func(t *testing.T) {
+		tail := log[strings.Index(log, "20:37 InitGame:"):]
+		report, err := New(WithFingerprints()).Parse(strings.NewReader(tail))
+		assert.NoError(t, err)
+		assert.Equal(t, expected[len(expected)-len(report):], fingerprints(report))
+	})
+
+	t.Run("Concurrently", func(t *testing.T) {
+		report, err := New(WithFingerprints()).ParseConcurrently(context.Background(), strings.NewReader(log), int64(len(log)), 4)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, fingerprints(report))
+	})
+
+	t.Run("Resumed", func(t *testing.T) {
+		// split in the middle of the first events of a match
+		split := strings.LastIndex(log[:strings.Index(log, "ClientConnect: 2")], "\n") + 1
+		state := &State{}
+		first, err := New(WithFingerprints(), WithState(state)).Parse(strings.NewReader(log[:split]))
+		assert.NoError(t, err)
+		second, err := New(WithFingerprints(), WithState(state)).Parse(strings.NewReader(log[split:]))
+		assert.NoError(t, err)
+		assert.Equal(t, expected, fingerprints(append(first, second...)))
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		report, err := New().Parse(strings.NewReader(log))
+		assert.NoError(t, err)
+		for _, fingerprint := range fingerprints(report) {
+			assert.Empty(t, fingerprint)
+		}
+	})
+}
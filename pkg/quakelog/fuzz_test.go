@@ -0,0 +1,215 @@
+package quakelog
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// qgamesLines returns the lines of the stock log, which seed the fuzz
+// targets.
+func qgamesLines(t testing.TB) []string {
+	content, err := os.ReadFile("../../assets/qgames.log")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+}
+
+// qgamesMatches returns the stock log split right before each InitGame line.
+func qgamesMatches(t testing.TB) []string {
+	matches := make([]string, 0)
+	var match strings.Builder
+	for _, line := range qgamesLines(t) {
+		if strings.Contains(line, "InitGame:") && match.Len() > 0 {
+			matches = append(matches, match.String())
+			match.Reset()
+		}
+		match.WriteString(line + "\n")
+	}
+
+	return append(matches, match.String())
+}
+
+func FuzzParseLogLine(f *testing.F) {
+	for _, line := range qgamesLines(f) {
+		f.Add(line)
+	}
+
+	f.Fuzz(func(t *testing.T, line string) {
+		eventType, matches := parseLogLine(line)
+		if eventType == "" {
+			if matches != nil {
+				t.Fatalf("matches %q without an event", matches)
+			}
+		} else if len(matches) != RegexPatterns[eventType].NumSubexp()+1 {
+			t.Fatalf("%s line %q has %d groups", eventType, line, len(matches))
+		}
+
+		// the line is applied both outside and inside a match
+		game := newGameState(nil)
+		game.parseLine(line)
+		game.parseLine("  0:00 InitGame: \\mapname\\q3dm17")
+		game.parseLine(line)
+		game.parseLine("  0:01 ShutdownGame:")
+		checkInvariants(t, game.finish())
+	})
+}
+
+func FuzzParseLines(f *testing.F) {
+	for _, match := range qgamesMatches(f) {
+		f.Add(match)
+	}
+	f.Add(orphansLog)
+	f.Add(testLog)
+
+	f.Fuzz(func(t *testing.T, log string) {
+		report, err := New().Parse(strings.NewReader(log))
+		if err != nil {
+			// lines longer than the scanner buffer
+			return
+		}
+		checkInvariants(t, report)
+
+		lines := make(chan string)
+		gameReport := make(chan GameReport)
+		go func() {
+			defer close(lines)
+			scanner := bufio.NewScanner(strings.NewReader(log))
+			for scanner.Scan() {
+				lines <- scanner.Text()
+			}
+		}()
+		go ParseLines(context.Background(), lines, gameReport, nil)
+
+		if streamed := <-gameReport; !assert.Equal(t, report, streamed) {
+			t.Fatal("ParseLines and Parse disagree")
+		}
+	})
+}
+
+// checkInvariants fails when report breaks an invariant every report holds.
+func checkInvariants(t testing.TB, report GameReport) {
+	t.Helper()
+
+	for _, match := range report {
+		for name, game := range match {
+			killsByMeans := 0
+			for _, kills := range game.KillsByMeans {
+				killsByMeans += kills
+			}
+			if killsByMeans != game.TotalKills {
+				t.Fatalf("%s: %d kills by means of death, %d total kills", name, killsByMeans, game.TotalKills)
+			}
+
+			players := make(map[string]bool, len(game.Players))
+			for _, player := range game.Players {
+				if players[player] {
+					t.Fatalf("%s: player %q listed twice", name, player)
+				}
+				players[player] = true
+			}
+			for player := range game.Kills {
+				if !players[player] {
+					t.Fatalf("%s: player %q has kills but is not listed", name, player)
+				}
+			}
+			if len(game.Kills) != len(game.Players) {
+				t.Fatalf("%s: %d players, %d with kills", name, len(game.Players), len(game.Kills))
+			}
+		}
+	}
+}
+
+// shiftGames renumbers the matches of report as if games matches and lines
+// lines were parsed before them.
+func shiftGames(report GameReport, games, lines int) GameReport {
+	rename := func(name string) string {
+		var number int
+		if _, err := fmt.Sscanf(name, "game_%d", &number); err != nil {
+			return name
+		}
+		return fmt.Sprintf("game_%d", number+games)
+	}
+
+	shifted := make(GameReport, 0, len(report))
+	for _, match := range report {
+		renamed := make(map[string]MatchReport, len(match))
+		for name, game := range match {
+			if game.Integrity != nil {
+				integrity := *game.Integrity
+				integrity.Issues = make([]Diagnostic, len(game.Integrity.Issues))
+				for i, issue := range game.Integrity.Issues {
+					issue.Line += lines
+					issue.Game = rename(issue.Game)
+					integrity.Issues[i] = issue
+				}
+				game.Integrity = &integrity
+			}
+			renamed[rename(name)] = game
+		}
+		shifted = append(shifted, renamed)
+	}
+
+	return shifted
+}
+
+func TestReportInvariants(t *testing.T) {
+	logs := map[string]string{
+		"qgames":  strings.Join(qgamesLines(t), "\n"),
+		"test":    testLog,
+		"orphans": orphansLog,
+	}
+	if content, err := os.ReadFile("../../assets/test.log"); assert.NoError(t, err) {
+		logs["test.log"] = string(content)
+	}
+
+	for name, log := range logs {
+		for _, policy := range []string{ORPHAN_DROP, ORPHAN_BUFFER, ORPHAN_ATTACH} {
+			t.Run(name+" "+policy, func(t *testing.T) {
+				report, err := New(WithOrphanPolicy(policy)).Parse(strings.NewReader(log))
+
+				assert.NoError(t, err)
+				checkInvariants(t, report)
+			})
+		}
+	}
+}
+
+func TestParseConcatenation(t *testing.T) {
+	matches := qgamesMatches(t)
+	// the lines before the first InitGame go with the first match, and every
+	// split point is right before an InitGame
+	matches[1] = matches[0] + matches[1]
+	matches = matches[1:]
+
+	for split := 1; split < len(matches); split++ {
+		if !strings.Contains(matches[split-1], "ShutdownGame:") {
+			// a match in progress at the end of the first log is left out of
+			// its report, while the second log closes it
+			continue
+		}
+
+		t.Run(fmt.Sprintf("Split after %d matches", split), func(t *testing.T) {
+			first := strings.Join(matches[:split], "")
+			second := strings.Join(matches[split:], "")
+
+			whole, err := New().Parse(strings.NewReader(first + second))
+			assert.NoError(t, err)
+			head, err := New().Parse(strings.NewReader(first))
+			assert.NoError(t, err)
+			tail, err := New().Parse(strings.NewReader(second))
+			assert.NoError(t, err)
+
+			lines := strings.Count(first, "\n")
+			expected := append(head, shiftGames(tail, len(head), lines)...)
+			assert.Equal(t, expected, whole)
+		})
+	}
+}
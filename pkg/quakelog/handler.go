@@ -0,0 +1,68 @@
+package quakelog
+
+// EventHandler computes a custom statistic of a match from its events, to be
+// added as a section of the match report. A new handler is created for every
+// match, so it only has to keep the state of one match.
+type EventHandler interface {
+	// HandleEvent receives every event of the match, from its match_start to
+	// its match_end, with the scoreboard right after the event was applied.
+	HandleEvent(event Event, scoreboard Scoreboard)
+	// Section returns the content of the section once the match ended, with
+	// its final report. A nil section is left out of the report.
+	Section(match MatchReport) any
+}
+
+type namedHandler struct {
+	name       string
+	newHandler func() EventHandler
+}
+
+// WithHandler registers a custom statistic: newHandler is called when every
+// match starts, and the section of the handler it returns is added to the
+// report of the match under name. A match resumed through WithState gets new
+// handlers, which only see the events parsed after the resume.
+func WithHandler(name string, newHandler func() EventHandler) Option {
+	return func(game *gameState) {
+		game.handlers = append(game.handlers, namedHandler{name: name, newHandler: newHandler})
+	}
+}
+
+// activeHandlers returns the handlers of the match in progress, creating them
+// on its first event or when it is restored.
+func (game *gameState) activeHandlers() []EventHandler {
+	if game.active == nil && len(game.handlers) > 0 {
+		game.active = make([]EventHandler, len(game.handlers))
+		for i, handler := range game.handlers {
+			game.active[i] = handler.newHandler()
+		}
+	}
+
+	return game.active
+}
+
+// endHandlers hands the end of the match to its handlers and collects their
+// sections.
+func (game *gameState) endHandlers() map[string]any {
+	handlers := game.active
+	if len(handlers) == 0 {
+		return nil
+	}
+	game.active = nil
+
+	match := game.matchReport
+	event := Event{Type: EVENT_MATCH_END, Time: game.time, Game: game.gameName(), Match: &match}
+	scoreboard := game.scoreboard()
+
+	sections := make(map[string]any)
+	for i, handler := range handlers {
+		handler.HandleEvent(event, scoreboard)
+		if section := handler.Section(match); section != nil {
+			sections[game.handlers[i].name] = section
+		}
+	}
+
+	if len(sections) == 0 {
+		return nil
+	}
+	return sections
+}
@@ -0,0 +1,95 @@
+package quakelog
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingHandler struct {
+	events []string
+}
+
+func (h *recordingHandler) HandleEvent(event Event, _ Scoreboard) {
+	h.events = append(h.events, event.Type)
+}
+
+func (h *recordingHandler) Section(match MatchReport) any {
+	return map[string]any{"events": h.events, "total_kills": match.TotalKills}
+}
+
+func TestWithHandler(t *testing.T) {
+	log := "  0:00 ClientConnect: 1\n" +
+		"  0:01 InitGame: \\mapname\\q3dm17\n" +
+		"  0:02 ClientUserinfoChanged: 2 n\\Player1\\t\\0\n" +
+		"  0:03 Kill: 1022 2 22: <world> killed Player1 by MOD_TRIGGER_HURT\n" +
+		"  0:04 ShutdownGame:\n" +
+		"  0:04 ShutdownGame:\n" +
+		"  0:05 InitGame: \\mapname\\q3dm6\n" +
+		"  0:06 InitGame: \\mapname\\q3dm7\n"
+
+	created := 0
+	var ended map[string]MatchReport
+	var endEvent Event
+	report, err := New(
+		WithHandler("timeline", func() EventHandler {
+			created++
+			return &recordingHandler{}
+		}),
+		OnMatchEnd(func(match map[string]MatchReport) {
+			ended = match
+		}),
+		OnEvent(func(event Event, _ Scoreboard) {
+			if event.Type == EVENT_MATCH_END && endEvent.Match == nil {
+				endEvent = event
+			}
+		}),
+	).Parse(strings.NewReader(log))
+
+	assert.NoError(t, err)
+	assert.Len(t, report, 2, "A repeated ShutdownGame does not report the match twice")
+	assert.Equal(t, 3, created, "A handler is created for every match")
+
+	assert.Equal(t, map[string]any{
+		"timeline": map[string]any{
+			"events":      []string{EVENT_MATCH_START, EVENT_PLAYER_INFO, EVENT_KILL, EVENT_MATCH_END},
+			"total_kills": 1,
+		},
+	}, report[0]["game_1"].Sections)
+	assert.Equal(t, []string{EVENT_MATCH_START, EVENT_MATCH_END},
+		report[1]["game_2"].Sections["timeline"].(map[string]any)["events"])
+
+	assert.Contains(t, ended["game_2"].Sections, "timeline", "OnMatchEnd gets the sections")
+	assert.Equal(t, report[0]["game_1"].Sections, endEvent.Match.Sections, "The match_end event gets the sections")
+}
+
+func TestWithHandlerNilSection(t *testing.T) {
+	report, err := New(WithHandler("nothing", func() EventHandler {
+		return nilSection{}
+	})).Parse(strings.NewReader("  0:00 InitGame: \\mapname\\q3dm17\n  0:01 ShutdownGame:\n"))
+
+	assert.NoError(t, err)
+	assert.Nil(t, report[0]["game_1"].Sections)
+}
+
+type nilSection struct{}
+
+func (nilSection) HandleEvent(Event, Scoreboard) {}
+
+func (nilSection) Section(MatchReport) any { return nil }
+
+func TestWithHandlerResumedMatch(t *testing.T) {
+	state := &State{}
+	_, err := New(WithState(state)).Parse(strings.NewReader("  0:00 InitGame: \\mapname\\q3dm17\n" +
+		"  0:01 ClientUserinfoChanged: 2 n\\Player1\\t\\0\n"))
+	assert.NoError(t, err)
+
+	report, err := New(WithState(state), WithHandler("timeline", func() EventHandler {
+		return &recordingHandler{}
+	})).Parse(strings.NewReader("  0:02 ShutdownGame:\n"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{EVENT_MATCH_END}, report[0]["game_1"].Sections["timeline"].(map[string]any)["events"],
+		"The handler of a resumed match only sees the events after the resume")
+}
@@ -0,0 +1,121 @@
+package quakelog
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Integrity statuses of a match.
+const (
+	INTEGRITY_OK         = "ok"
+	INTEGRITY_INCOMPLETE = "incomplete"
+	INTEGRITY_CORRUPTED  = "corrupted"
+)
+
+// Integrity lists the issues that damaged a match. A match without
+// ShutdownGame is incomplete, while one with lines cut off, a clock going
+// back or kills of clients that never joined is corrupted.
+type Integrity struct {
+	Status string       `json:"status"`
+	Issues []Diagnostic `json:"issues"`
+}
+
+// IntegrityStatus returns the integrity status of the match, which is
+// INTEGRITY_OK when it has no issues.
+func (match MatchReport) IntegrityStatus() string {
+	if match.Integrity == nil {
+		return INTEGRITY_OK
+	}
+	return match.Integrity.Status
+}
+
+// integrityKinds are the anomalies that damage the match they happen in.
+var integrityKinds = map[string]bool{
+	DIAGNOSTIC_SPLICED_LINE:     true,
+	DIAGNOSTIC_CLOCK_JUMP:       true,
+	DIAGNOSTIC_UNKNOWN_CLIENT:   true,
+	DIAGNOSTIC_MISSING_SHUTDOWN: true,
+}
+
+// addIssue records an anomaly that damaged the match in progress.
+func (game *gameState) addIssue(diagnostic Diagnostic) {
+	integrity := game.matchReport.Integrity
+	if integrity == nil {
+		integrity = &Integrity{Status: INTEGRITY_INCOMPLETE}
+		game.matchReport.Integrity = integrity
+	}
+
+	integrity.Issues = append(integrity.Issues, diagnostic)
+	if diagnostic.Kind != DIAGNOSTIC_MISSING_SHUTDOWN {
+		integrity.Status = INTEGRITY_CORRUPTED
+	}
+}
+
+// spliced returns the entry starting in the middle of line, which happens when
+// the server stopped while writing the line, then wrote the next one right
+// after it.
+func (game *gameState) spliced(line string) (string, bool) {
+	start := 0
+	if loc := LineEntry.FindStringIndex(line); loc != nil {
+		start = loc[1]
+	}
+
+	for _, loc := range LineSplice.FindAllStringSubmatchIndex(line[start:], -1) {
+		entry := ""
+		if loc[4] >= 0 {
+			entry = line[start+loc[4] : start+loc[5]]
+		}
+		// an unknown entry is more likely part of the line, like a chat message
+		if entry == "" || RegexPatterns[entry] != nil || game.ignored[entry] {
+			return line[start+loc[2]:], true
+		}
+	}
+
+	return "", false
+}
+
+// checkClock reports an entry of the match in progress written before the
+// previous one. The server clock only goes back when a new match starts, whose
+// separator is already written with the new clock.
+func (game *gameState) checkClock(line, eventType string) {
+	if entry := LineEntry.FindStringSubmatch(line); entry == nil || entry[2] == "" {
+		return
+	}
+
+	if game.gameStarted && eventType != INIT_GAME && game.lastTime != "" && seconds(game.time) < seconds(game.lastTime) {
+		game.diagnose(DIAGNOSTIC_CLOCK_JUMP, "time went back from %s", game.lastTime)
+	}
+	game.lastTime = game.time
+}
+
+// checkClients reports the players of a kill who never joined the match in
+// progress.
+func (game *gameState) checkClients(killerID, victimID int) {
+	if !game.gameStarted || game.attaching {
+		return
+	}
+
+	if _, ok := game.players[killerID]; !ok && killerID != WORLD_ID {
+		game.diagnose(DIAGNOSTIC_UNKNOWN_CLIENT, "kill by client %d, who is not in the match", killerID)
+	}
+	if _, ok := game.players[victimID]; !ok && victimID != killerID {
+		game.diagnose(DIAGNOSTIC_UNKNOWN_CLIENT, "kill of client %d, who is not in the match", victimID)
+	}
+}
+
+// closeUnfinished ends the match in progress when the next one starts
+// without a ShutdownGame.
+func (game *gameState) closeUnfinished() {
+	game.diagnose(DIAGNOSTIC_MISSING_SHUTDOWN, "match ended without ShutdownGame")
+	game.gameStarted = false
+	game.endGame()
+}
+
+// seconds converts the time of a log line, formatted as minutes:seconds, to
+// seconds.
+func seconds(time string) int {
+	minutes, secs, _ := strings.Cut(time, ":")
+	m, _ := strconv.Atoi(minutes)
+	s, _ := strconv.Atoi(secs)
+	return m*60 + s
+}
@@ -0,0 +1,196 @@
+package quakelog
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIntegrity(t *testing.T) {
+	tests := []struct {
+		name   string
+		lines  []string
+		status string
+		kinds  []string
+		kills  int
+	}{
+		{
+			name: "Intact match",
+			lines: []string{
+				"  0:02 Kill: 2 3 10: Player1 killed Player2 by MOD_RAILGUN",
+			},
+			status: INTEGRITY_OK,
+			kills:  1,
+		},
+		{
+			name: "Line cut off by the next entry",
+			lines: []string{
+				"  0:02 Kill: 2 3 10: Player1 kil  0:03 Kill: 3 2 10: Player2 killed Player1 by MOD_RAILGUN",
+			},
+			status: INTEGRITY_CORRUPTED,
+			kinds:  []string{DIAGNOSTIC_SPLICED_LINE},
+			kills:  1,
+		},
+		{
+			name: "Line cut off by an entry the reports do not use",
+			lines: []string{
+				"  0:02 Kill: 2 3 10: Player1 kil  0:03 Item: 2 weapon_rocketlauncher",
+			},
+			status: INTEGRITY_CORRUPTED,
+			kinds:  []string{DIAGNOSTIC_SPLICED_LINE},
+		},
+		{
+			name: "Chat message with a time",
+			lines: []string{
+				"  0:02 say: Player1: meet at 10:30 sharp: ok?",
+			},
+			status: INTEGRITY_OK,
+		},
+		{
+			name: "Clock going back",
+			lines: []string{
+				"  0:05 Kill: 2 3 10: Player1 killed Player2 by MOD_RAILGUN",
+				"  0:03 Kill: 3 2 10: Player2 killed Player1 by MOD_RAILGUN",
+			},
+			status: INTEGRITY_CORRUPTED,
+			kinds:  []string{DIAGNOSTIC_CLOCK_JUMP},
+			kills:  2,
+		},
+		{
+			name: "Kill by an unknown client",
+			lines: []string{
+				"  0:02 Kill: 5 3 10: Player5 killed Player2 by MOD_RAILGUN",
+			},
+			status: INTEGRITY_CORRUPTED,
+			kinds:  []string{DIAGNOSTIC_UNKNOWN_CLIENT},
+			kills:  1,
+		},
+		{
+			name: "Kill of an unknown client",
+			lines: []string{
+				"  0:02 Kill: 1022 5 22: <world> killed Player5 by MOD_TRIGGER_HURT",
+			},
+			status: INTEGRITY_CORRUPTED,
+			kinds:  []string{DIAGNOSTIC_UNKNOWN_CLIENT},
+			kills:  1,
+		},
+		{
+			name: "Missing shutdown",
+			lines: []string{
+				"  0:02 Kill: 2 3 10: Player1 killed Player2 by MOD_RAILGUN",
+				"  0:00 InitGame: \\mapname\\q3dm6",
+			},
+			status: INTEGRITY_INCOMPLETE,
+			kinds:  []string{DIAGNOSTIC_MISSING_SHUTDOWN},
+			kills:  1,
+		},
+		{
+			name: "Missing shutdown of a corrupted match",
+			lines: []string{
+				"  0:02 Kill: 5 3 10: Player5 killed Player2 by MOD_RAILGUN",
+				"  0:00 InitGame: \\mapname\\q3dm6",
+			},
+			status: INTEGRITY_CORRUPTED,
+			kinds:  []string{DIAGNOSTIC_UNKNOWN_CLIENT, DIAGNOSTIC_MISSING_SHUTDOWN},
+			kills:  1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			log := "  0:00 InitGame: \\mapname\\q3dm17\n" +
+				"  0:01 ClientUserinfoChanged: 2 n\\Player1\\t\\0\n" +
+				"  0:01 ClientUserinfoChanged: 3 n\\Player2\\t\\0\n" +
+				strings.Join(tc.lines, "\n") + "\n" +
+				"  0:09 ShutdownGame:\n"
+
+			diagnostics := &Diagnostics{}
+			report, err := New(OnDiagnostic(diagnostics.Add)).Parse(strings.NewReader(log))
+
+			assert.NoError(t, err)
+			match := report[0]["game_1"]
+			assert.Equal(t, tc.status, match.IntegrityStatus())
+			assert.Equal(t, tc.kills, match.TotalKills)
+			if tc.kinds == nil {
+				assert.Nil(t, match.Integrity)
+				assert.Empty(t, diagnostics.List())
+				return
+			}
+
+			kinds := make([]string, 0, len(match.Integrity.Issues))
+			for _, issue := range match.Integrity.Issues {
+				kinds = append(kinds, issue.Kind)
+			}
+			assert.Equal(t, tc.kinds, kinds)
+			assert.Equal(t, diagnostics.List(), match.Integrity.Issues, "Every issue is also reported as a diagnostic")
+		})
+	}
+}
+
+func TestIntegritySplicedLine(t *testing.T) {
+	log := "  0:00 InitGame: \\mapname\\q3dm17\n" +
+		"  0:01 ClientUserinfoChanged: 2 n\\Player1\\t\\0\n" +
+		"  0:01 ClientUserinfoChanged: 3 n\\Player2\\t\\0\n" +
+		"  0:02 Kill: 2 3 10: Player1 kil  0:03 Kill: 3 2 10: Player2 killed Player1 by MOD_RAILGUN\n" +
+		"  0:04 ShutdownGame:\n"
+
+	report, err := New().Parse(strings.NewReader(log))
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"Player1 (ID 2)": 0, "Player2 (ID 3)": 1}, report[0]["game_1"].Kills, "The entry glued to the cut off line is kept")
+	assert.Equal(t, []Diagnostic{{
+		Line:   4,
+		Game:   "game_1",
+		Time:   "0:02",
+		Kind:   DIAGNOSTIC_SPLICED_LINE,
+		Reason: "line cut off by the next entry",
+		Text:   "  0:02 Kill: 2 3 10: Player1 kil  0:03 Kill: 3 2 10: Player2 killed Player1 by MOD_RAILGUN",
+	}}, report[0]["game_1"].Integrity.Issues)
+}
+
+func TestIntegritySplicedInitGame(t *testing.T) {
+	log := "  0:00 InitGame: \\mapname\\q3dm17\n" +
+		"  0:01 ClientUserinfoChanged: 2 n\\Player1\\t\\0\n" +
+		"  0:02 Kill: 1022 2 22: <world> kil  0:00 InitGame: \\mapname\\q3dm6\n" +
+		"  0:01 ClientUserinfoChanged: 3 n\\Player2\\t\\0\n" +
+		"  0:02 InitGame: \\mapname\\q3dm17\n" +
+		"  0:03 ShutdownGame:\n"
+
+	report, err := New().Parse(strings.NewReader(log))
+	assert.NoError(t, err)
+	assert.Len(t, report, 3)
+	assert.Equal(t, INTEGRITY_CORRUPTED, report[0]["game_1"].IntegrityStatus(), "The match cut off by the InitGame is damaged")
+
+	concurrent, err := New().ParseConcurrently(context.Background(), strings.NewReader(log), int64(len(log)), 4)
+	assert.NoError(t, err)
+	assert.Equal(t, report, concurrent)
+}
+
+func TestIntegrityQgames(t *testing.T) {
+	content, err := os.ReadFile("../../assets/qgames.log")
+	assert.NoError(t, err)
+
+	report, err := New().Parse(strings.NewReader(string(content)))
+	assert.NoError(t, err)
+
+	for _, match := range report {
+		for name, game := range match {
+			if name == "game_2" {
+				assert.Equal(t, INTEGRITY_CORRUPTED, game.IntegrityStatus(), "game_2 is cut off by a server restart")
+				continue
+			}
+			assert.Equal(t, INTEGRITY_OK, game.IntegrityStatus(), name)
+		}
+	}
+
+	concurrent, err := New().ParseConcurrently(context.Background(), strings.NewReader(string(content)), int64(len(content)), 4)
+	assert.NoError(t, err)
+	for i, match := range report {
+		for name, game := range match {
+			assert.Equal(t, game.Integrity, concurrent[i][name].Integrity, "The issues of a match closed at the end of a segment are the same")
+		}
+	}
+}
@@ -0,0 +1,238 @@
+package quakelog
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Categories of the means of death.
+const (
+	CATEGORY_WEAPON      = "weapon"
+	CATEGORY_ENVIRONMENT = "environment"
+	CATEGORY_SELF        = "self"
+)
+
+// Built-in means of death tables.
+const (
+	MEANS_BASEQ3      = "baseq3"
+	MEANS_MISSIONPACK = "missionpack"
+	MEANS_NONE        = "none"
+)
+
+// MeansOfDeath describes a means of death: ID is the number of the Kill lines
+// (the third one), Name the text ending them, Category one of the CATEGORY_*
+// constants and Label a name to display.
+type MeansOfDeath struct {
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	Category string `json:"category,omitempty"`
+	Label    string `json:"label,omitempty"`
+}
+
+// MeansRegistry is the table of the means of death a server can log, used to
+// list them in KillsByMeans and to describe the kill events. It is not
+// modified once built, so it can be shared by several parsers.
+type MeansRegistry struct {
+	means  []MeansOfDeath
+	byID   map[int]int
+	byName map[string]int
+}
+
+// NewMeansRegistry builds a registry from the given tables, where a means of
+// death replaces a previous one with the same ID or name.
+func NewMeansRegistry(tables ...[]MeansOfDeath) *MeansRegistry {
+	registry := &MeansRegistry{
+		means:  make([]MeansOfDeath, 0),
+		byID:   make(map[int]int),
+		byName: make(map[string]int),
+	}
+
+	for _, table := range tables {
+		for _, means := range table {
+			registry.add(means)
+		}
+	}
+
+	return registry
+}
+
+func (registry *MeansRegistry) add(means MeansOfDeath) {
+	kept := registry.means[:0]
+	for _, other := range registry.means {
+		if other.ID != means.ID && other.Name != means.Name {
+			kept = append(kept, other)
+		}
+	}
+	registry.means = append(kept, means)
+
+	clear(registry.byID)
+	clear(registry.byName)
+	for i, means := range registry.means {
+		registry.byID[means.ID] = i
+		registry.byName[means.Name] = i
+	}
+}
+
+// ByID returns the means of death with the given number.
+func (registry *MeansRegistry) ByID(ID int) (MeansOfDeath, bool) {
+	i, ok := registry.byID[ID]
+	if !ok {
+		return MeansOfDeath{}, false
+	}
+	return registry.means[i], true
+}
+
+// ByName returns the means of death with the given name.
+func (registry *MeansRegistry) ByName(name string) (MeansOfDeath, bool) {
+	i, ok := registry.byName[name]
+	if !ok {
+		return MeansOfDeath{}, false
+	}
+	return registry.means[i], true
+}
+
+// Means returns every means of death of the registry, sorted by ID.
+func (registry *MeansRegistry) Means() []MeansOfDeath {
+	means := append([]MeansOfDeath(nil), registry.means...)
+	sort.Slice(means, func(i, j int) bool {
+		return means[i].ID < means[j].ID
+	})
+	return means
+}
+
+// WithMeansRegistry describes the means of death with registry instead of
+// DefaultMeansRegistry.
+func WithMeansRegistry(registry *MeansRegistry) Option {
+	return func(game *gameState) {
+		game.means = registry
+	}
+}
+
+// resolveMeans cross-checks the means of death written at the end of a line
+// with its ID. The name in the registry replaces a missing or cut off one,
+// while a name that does not match the ID is kept and reported, as the
+// server may number its means of death differently.
+func (game *gameState) resolveMeans(ID int, name string) string {
+	registered, known := game.means.ByID(ID)
+
+	switch {
+	case name == "" && known:
+		game.diagnose(DIAGNOSTIC_MEANS_MISSING, "missing means of death, %d is %s", ID, registered.Name)
+		return registered.Name
+	case name == "":
+		game.diagnose(DIAGNOSTIC_MEANS_MISSING, "missing means of death, %d is unknown", ID)
+		return MOD_UNKNOWN
+	case !known || registered.Name == name:
+		return name
+	}
+
+	if _, ok := game.means.ByName(name); !ok && strings.HasPrefix(registered.Name, name) {
+		game.diagnose(DIAGNOSTIC_MEANS_TRUNCATED, "means of death %s cut off, %d is %s", name, ID, registered.Name)
+		return registered.Name
+	}
+
+	game.diagnose(DIAGNOSTIC_MEANS_MISMATCH, "means of death %s does not match %d, which is %s", name, ID, registered.Name)
+	return name
+}
+
+// BuiltinMeans returns a copy of a built-in table: MEANS_BASEQ3 for the stock
+// game, MEANS_MISSIONPACK for Team Arena and the servers built with its
+// weapons, like OpenArena, and MEANS_NONE for an empty one.
+func BuiltinMeans(name string) ([]MeansOfDeath, bool) {
+	switch name {
+	case MEANS_BASEQ3:
+		return baseq3Means(), true
+	case MEANS_MISSIONPACK:
+		return missionpackMeans(), true
+	case MEANS_NONE:
+		return []MeansOfDeath{}, true
+	}
+	return nil, false
+}
+
+// DefaultMeansRegistry returns the registry of the missionpack means of death,
+// which is a superset of the baseq3 names.
+func DefaultMeansRegistry() *MeansRegistry {
+	return defaultMeans
+}
+
+var defaultMeans = NewMeansRegistry(missionpackMeans())
+
+// MeansConfig is the JSON configuration of a registry: the means of death are
+// added to the built-in table named by Extends, MEANS_MISSIONPACK when empty.
+type MeansConfig struct {
+	Extends string         `json:"extends,omitempty"`
+	Means   []MeansOfDeath `json:"means"`
+}
+
+// ReadMeansRegistry builds a registry from the MeansConfig in r.
+func ReadMeansRegistry(r io.Reader) (*MeansRegistry, error) {
+	var config MeansConfig
+	if err := json.NewDecoder(r).Decode(&config); err != nil {
+		return nil, fmt.Errorf("error parsing means of death: %w", err)
+	}
+
+	if config.Extends == "" {
+		config.Extends = MEANS_MISSIONPACK
+	}
+	base, ok := BuiltinMeans(config.Extends)
+	if !ok {
+		return nil, fmt.Errorf("unknown means of death table %q", config.Extends)
+	}
+
+	for _, means := range config.Means {
+		if means.Name == "" {
+			return nil, fmt.Errorf("means of death %d has no name", means.ID)
+		}
+	}
+
+	return NewMeansRegistry(base, config.Means), nil
+}
+
+// baseq3Means are the means of death of the stock game, numbered as in its
+// meansOfDeath_t.
+func baseq3Means() []MeansOfDeath {
+	return []MeansOfDeath{
+		{ID: 0, Name: MOD_UNKNOWN, Category: CATEGORY_ENVIRONMENT, Label: "Unknown"},
+		{ID: 1, Name: MOD_SHOTGUN, Category: CATEGORY_WEAPON, Label: "Shotgun"},
+		{ID: 2, Name: MOD_GAUNTLET, Category: CATEGORY_WEAPON, Label: "Gauntlet"},
+		{ID: 3, Name: MOD_MACHINEGUN, Category: CATEGORY_WEAPON, Label: "Machinegun"},
+		{ID: 4, Name: MOD_GRENADE, Category: CATEGORY_WEAPON, Label: "Grenade"},
+		{ID: 5, Name: MOD_GRENADE_SPLASH, Category: CATEGORY_WEAPON, Label: "Grenade splash"},
+		{ID: 6, Name: MOD_ROCKET, Category: CATEGORY_WEAPON, Label: "Rocket"},
+		{ID: 7, Name: MOD_ROCKET_SPLASH, Category: CATEGORY_WEAPON, Label: "Rocket splash"},
+		{ID: 8, Name: MOD_PLASMA, Category: CATEGORY_WEAPON, Label: "Plasma"},
+		{ID: 9, Name: MOD_PLASMA_SPLASH, Category: CATEGORY_WEAPON, Label: "Plasma splash"},
+		{ID: 10, Name: MOD_RAILGUN, Category: CATEGORY_WEAPON, Label: "Railgun"},
+		{ID: 11, Name: MOD_LIGHTNING, Category: CATEGORY_WEAPON, Label: "Lightning gun"},
+		{ID: 12, Name: MOD_BFG, Category: CATEGORY_WEAPON, Label: "BFG"},
+		{ID: 13, Name: MOD_BFG_SPLASH, Category: CATEGORY_WEAPON, Label: "BFG splash"},
+		{ID: 14, Name: MOD_WATER, Category: CATEGORY_ENVIRONMENT, Label: "Drowned"},
+		{ID: 15, Name: MOD_SLIME, Category: CATEGORY_ENVIRONMENT, Label: "Slime"},
+		{ID: 16, Name: MOD_LAVA, Category: CATEGORY_ENVIRONMENT, Label: "Lava"},
+		{ID: 17, Name: MOD_CRUSH, Category: CATEGORY_ENVIRONMENT, Label: "Crushed"},
+		{ID: 18, Name: MOD_TELEFRAG, Category: CATEGORY_WEAPON, Label: "Telefrag"},
+		{ID: 19, Name: MOD_FALLING, Category: CATEGORY_ENVIRONMENT, Label: "Fall"},
+		{ID: 20, Name: MOD_SUICIDE, Category: CATEGORY_SELF, Label: "Suicide"},
+		{ID: 21, Name: MOD_TARGET_LASER, Category: CATEGORY_ENVIRONMENT, Label: "Laser"},
+		{ID: 22, Name: MOD_TRIGGER_HURT, Category: CATEGORY_ENVIRONMENT, Label: "Trigger hurt"},
+		{ID: 23, Name: MOD_GRAPPLE, Category: CATEGORY_WEAPON, Label: "Grapple"},
+	}
+}
+
+// missionpackMeans are the means of death of Team Arena, which adds its
+// weapons before MOD_GRAPPLE.
+func missionpackMeans() []MeansOfDeath {
+	means := baseq3Means()
+	return append(means[:23],
+		MeansOfDeath{ID: 23, Name: MOD_NAIL, Category: CATEGORY_WEAPON, Label: "Nailgun"},
+		MeansOfDeath{ID: 24, Name: MOD_CHAINGUN, Category: CATEGORY_WEAPON, Label: "Chaingun"},
+		MeansOfDeath{ID: 25, Name: MOD_PROXIMITY_MINE, Category: CATEGORY_WEAPON, Label: "Proximity mine"},
+		MeansOfDeath{ID: 26, Name: MOD_KAMIKAZE, Category: CATEGORY_WEAPON, Label: "Kamikaze"},
+		MeansOfDeath{ID: 27, Name: MOD_JUICED, Category: CATEGORY_WEAPON, Label: "Juiced"},
+		MeansOfDeath{ID: 28, Name: MOD_GRAPPLE, Category: CATEGORY_WEAPON, Label: "Grapple"},
+	)
+}
@@ -0,0 +1,241 @@
+package quakelog
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuiltinMeans(t *testing.T) {
+	tests := []struct {
+		table   string
+		grapple int
+		length  int
+	}{
+		{table: MEANS_BASEQ3, grapple: 23, length: 24},
+		{table: MEANS_MISSIONPACK, grapple: 28, length: 29},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.table, func(t *testing.T) {
+			means, ok := BuiltinMeans(tc.table)
+			assert.True(t, ok)
+			assert.Len(t, means, tc.length)
+
+			registry := NewMeansRegistry(means)
+			grapple, ok := registry.ByName(MOD_GRAPPLE)
+			assert.True(t, ok)
+			assert.Equal(t, tc.grapple, grapple.ID)
+
+			for i, m := range registry.Means() {
+				assert.Equal(t, i, m.ID, "The means of death are numbered from 0")
+			}
+		})
+	}
+
+	_, ok := BuiltinMeans("quake4")
+	assert.False(t, ok)
+}
+
+func TestNewMeansRegistry(t *testing.T) {
+	registry := NewMeansRegistry(
+		[]MeansOfDeath{
+			{ID: 1, Name: "MOD_A", Category: CATEGORY_WEAPON},
+			{ID: 2, Name: "MOD_B", Category: CATEGORY_WEAPON},
+			{ID: 3, Name: "MOD_C", Category: CATEGORY_WEAPON},
+		},
+		[]MeansOfDeath{
+			{ID: 2, Name: "MOD_D", Category: CATEGORY_ENVIRONMENT},
+			{ID: 4, Name: "MOD_C", Category: CATEGORY_SELF},
+		},
+	)
+
+	assert.Equal(t, []MeansOfDeath{
+		{ID: 1, Name: "MOD_A", Category: CATEGORY_WEAPON},
+		{ID: 2, Name: "MOD_D", Category: CATEGORY_ENVIRONMENT},
+		{ID: 4, Name: "MOD_C", Category: CATEGORY_SELF},
+	}, registry.Means())
+
+	_, ok := registry.ByName("MOD_B")
+	assert.False(t, ok, "A means of death is replaced by a later one with the same ID")
+	_, ok = registry.ByID(3)
+	assert.False(t, ok, "A means of death is replaced by a later one with the same name")
+	means, ok := registry.ByID(4)
+	assert.True(t, ok)
+	assert.Equal(t, "MOD_C", means.Name)
+}
+
+func TestReadMeansRegistry(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		means  int
+		errMsg string
+	}{
+		{
+			name:   "Extends missionpack by default",
+			config: `{"means": [{"id": 29, "name": "MOD_LASERGUN", "category": "weapon", "label": "Laser gun"}]}`,
+			means:  30,
+		},
+		{
+			name:   "Extends baseq3",
+			config: `{"extends": "baseq3", "means": [{"id": 23, "name": "MOD_HOOK", "category": "weapon"}]}`,
+			means:  24,
+		},
+		{
+			name:   "Replaces the built-in means",
+			config: `{"extends": "none", "means": [{"id": 12, "name": "UT_MOD_KNIFE", "category": "weapon", "label": "Knife"}]}`,
+			means:  1,
+		},
+		{
+			name:   "Unknown table",
+			config: `{"extends": "quake4", "means": []}`,
+			errMsg: `unknown means of death table "quake4"`,
+		},
+		{
+			name:   "Missing name",
+			config: `{"means": [{"id": 40}]}`,
+			errMsg: "means of death 40 has no name",
+		},
+		{
+			name:   "Invalid JSON",
+			config: `{"means": [`,
+			errMsg: "error parsing means of death",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			registry, err := ReadMeansRegistry(strings.NewReader(tc.config))
+			if tc.errMsg != "" {
+				assert.ErrorContains(t, err, tc.errMsg)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Len(t, registry.Means(), tc.means)
+		})
+	}
+}
+
+func TestWithMeansRegistry(t *testing.T) {
+	log := "  0:00 InitGame: \\mapname\\ut4_turnpike\n" +
+		"  0:01 ClientUserinfoChanged: 2 n\\Player1\\t\\0\n" +
+		"  0:01 ClientUserinfoChanged: 3 n\\Player2\\t\\0\n" +
+		"  0:02 Kill: 2 3 12: Player1 killed Player2 by UT_MOD_KNIFE\n" +
+		"  0:03 Kill: 3 2 19: Player2 killed Player1 by UT_MOD_LR300\n" +
+		"  0:04 ShutdownGame:\n"
+	registry := NewMeansRegistry([]MeansOfDeath{
+		{ID: 12, Name: "UT_MOD_KNIFE", Category: CATEGORY_WEAPON, Label: "Knife"},
+		{ID: 14, Name: "UT_MOD_BERETTA", Category: CATEGORY_WEAPON, Label: "Beretta"},
+	})
+
+	kills := make([]KillEvent, 0)
+	report, err := New(WithMeansRegistry(registry), OnEvent(func(event Event, _ Scoreboard) {
+		if event.Type == EVENT_KILL {
+			kills = append(kills, *event.Kill)
+		}
+	})).Parse(strings.NewReader(log))
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"UT_MOD_KNIFE": 1, "UT_MOD_BERETTA": 0, "UT_MOD_LR300": 1},
+		report[0]["game_1"].KillsByMeans, "Only the registered means of death are listed without kills")
+	assert.Equal(t, []KillEvent{
+		{KillerID: 2, Killer: "Player1", VictimID: 3, Victim: "Player2", Method: "UT_MOD_KNIFE", MethodID: 12, Category: CATEGORY_WEAPON, Label: "Knife"},
+		{KillerID: 3, Killer: "Player2", VictimID: 2, Victim: "Player1", Method: "UT_MOD_LR300", MethodID: 19},
+	}, kills)
+}
+
+func TestResolveMeans(t *testing.T) {
+	tests := []struct {
+		name       string
+		kill       string
+		method     string
+		diagnostic string
+	}{
+		{
+			name:   "Matching ID and name",
+			kill:   "Kill: 2 3 10: Player1 killed Player2 by MOD_RAILGUN",
+			method: MOD_RAILGUN,
+		},
+		{
+			name:       "Missing name",
+			kill:       "Kill: 2 3 10: Player1 killed Player2 by ",
+			method:     MOD_RAILGUN,
+			diagnostic: DIAGNOSTIC_MEANS_MISSING,
+		},
+		{
+			name:       "Missing name and unknown ID",
+			kill:       "Kill: 2 3 99: Player1 killed Player2",
+			method:     MOD_UNKNOWN,
+			diagnostic: DIAGNOSTIC_MEANS_MISSING,
+		},
+		{
+			name:       "Truncated name",
+			kill:       "Kill: 2 3 7: Player1 killed Player2 by MOD_ROCKET_SPL",
+			method:     MOD_ROCKET_SPLASH,
+			diagnostic: DIAGNOSTIC_MEANS_TRUNCATED,
+		},
+		{
+			name:       "Name of another ID",
+			kill:       "Kill: 2 3 23: Player1 killed Player2 by MOD_GRAPPLE",
+			method:     MOD_GRAPPLE,
+			diagnostic: DIAGNOSTIC_MEANS_MISMATCH,
+		},
+		{
+			name:       "Renamed means of death",
+			kill:       "Kill: 2 3 10: Player1 killed Player2 by MOD_INSTAGIB",
+			method:     "MOD_INSTAGIB",
+			diagnostic: DIAGNOSTIC_MEANS_MISMATCH,
+		},
+		{
+			name:   "Unknown ID and name",
+			kill:   "Kill: 2 3 99: Player1 killed Player2 by MOD_INSTAGIB",
+			method: "MOD_INSTAGIB",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			log := "  0:00 InitGame: \\mapname\\q3dm17\n" +
+				"  0:00 ClientUserinfoChanged: 2 n\\Player1\\t\\0\n" +
+				"  0:00 ClientUserinfoChanged: 3 n\\Player2\\t\\0\n" +
+				"  0:01 " + tc.kill + "\n" +
+				"  0:02 ShutdownGame:\n"
+
+			diagnostics := make([]Diagnostic, 0)
+			report, err := New(OnDiagnostic(func(diagnostic Diagnostic) {
+				diagnostics = append(diagnostics, diagnostic)
+			})).Parse(strings.NewReader(log))
+
+			assert.NoError(t, err)
+			assert.Equal(t, 1, report[0]["game_1"].KillsByMeans[tc.method])
+			if tc.diagnostic == "" {
+				assert.Empty(t, diagnostics)
+				return
+			}
+			assert.Len(t, diagnostics, 1)
+			assert.Equal(t, tc.diagnostic, diagnostics[0].Kind)
+			assert.Equal(t, "game_1", diagnostics[0].Game)
+			assert.Equal(t, "0:01", diagnostics[0].Time)
+			assert.Equal(t, "  0:01 "+tc.kill, diagnostics[0].Text)
+		})
+	}
+}
+
+func TestResolveMeansQgames(t *testing.T) {
+	content, err := os.ReadFile("../../assets/qgames.log")
+	assert.NoError(t, err)
+
+	diagnostics := make([]Diagnostic, 0)
+	_, err = New(OnDiagnostic(func(diagnostic Diagnostic) {
+		if strings.HasPrefix(diagnostic.Kind, "means_") {
+			diagnostics = append(diagnostics, diagnostic)
+		}
+	})).Parse(strings.NewReader(string(content)))
+
+	assert.NoError(t, err)
+	assert.Empty(t, diagnostics, "The IDs of the stock log match the missionpack names")
+}
@@ -0,0 +1,74 @@
+package quakelog
+
+import "fmt"
+
+// Totals are the aggregates of a whole report, like a season played on
+// several servers. Kills sums the score of each player over the matches by
+// name, since the client IDs change from a match to another. The UNSCOPED
+// reports are not matches, but their kills are added up too.
+type Totals struct {
+	Matches      int            `json:"matches"`
+	TotalKills   int            `json:"total_kills"`
+	Kills        map[string]int `json:"kills"`
+	KillsByMeans map[string]int `json:"kills_by_means"`
+}
+
+// Merge combines reports, from different logs or servers, into a single one
+// with their matches in order, numbered from game_1. A match whose
+// Fingerprint was already merged from another report is dropped, since both
+// reports were parsed from logs sharing it. Matches without Fingerprint, or
+// with the same one in a single report, are always kept. The UNSCOPED
+// reports keep their name, in the place they had among the matches.
+func Merge(reports ...GameReport) GameReport {
+	merged := make(GameReport, 0)
+	merges := make(map[string]int)
+	games := 0
+
+	for i, report := range reports {
+		for _, game := range report {
+			for name, match := range game {
+				if name == UNSCOPED {
+					merged = append(merged, map[string]MatchReport{UNSCOPED: match})
+					continue
+				}
+				if merge, ok := merges[match.Fingerprint]; ok && merge != i {
+					continue
+				}
+				if match.Fingerprint != "" {
+					merges[match.Fingerprint] = i
+				}
+
+				games++
+				merged = append(merged, map[string]MatchReport{fmt.Sprintf("game_%d", games): match})
+			}
+		}
+	}
+
+	return merged
+}
+
+// Totals adds up the matches of the report.
+func (report GameReport) Totals() Totals {
+	totals := Totals{
+		Kills:        make(map[string]int),
+		KillsByMeans: make(map[string]int),
+	}
+
+	for _, game := range report {
+		for name, match := range game {
+			if name != UNSCOPED {
+				totals.Matches++
+			}
+			totals.TotalKills += match.TotalKills
+			for player, kills := range match.Kills {
+				name, _ := splitPlayer(player)
+				totals.Kills[name] += kills
+			}
+			for means, kills := range match.KillsByMeans {
+				totals.KillsByMeans[means] += kills
+			}
+		}
+	}
+
+	return totals
+}
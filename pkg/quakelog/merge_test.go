@@ -0,0 +1,109 @@
+package quakelog
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMerge(t *testing.T) {
+	match := func(kills int, fingerprint string) MatchReport {
+		return MatchReport{TotalKills: kills, Fingerprint: fingerprint}
+	}
+
+	tests := []struct {
+		name     string
+		reports  []GameReport
+		expected GameReport
+	}{
+		{
+			name:     "No reports",
+			expected: GameReport{},
+		},
+		{
+			name: "Renumbered games",
+			reports: []GameReport{
+				{{"game_1": match(1, "")}, {"game_2": match(2, "")}},
+				{},
+				{{"game_1": match(3, "")}},
+			},
+			expected: GameReport{
+				{"game_1": match(1, "")},
+				{"game_2": match(2, "")},
+				{"game_3": match(3, "")},
+			},
+		},
+		{
+			name: "Overlapping reports",
+			reports: []GameReport{
+				{{"game_1": match(1, "a")}, {"game_2": match(2, "b")}},
+				{{"game_1": match(2, "b")}, {"game_2": match(3, "c")}},
+				{{"game_1": match(1, "a")}, {"game_2": match(3, "c")}, {"game_3": match(4, "d")}},
+			},
+			expected: GameReport{
+				{"game_1": match(1, "a")},
+				{"game_2": match(2, "b")},
+				{"game_3": match(3, "c")},
+				{"game_4": match(4, "d")},
+			},
+		},
+		{
+			name: "Unscoped reports",
+			reports: []GameReport{
+				{{"game_1": match(1, "a")}, {UNSCOPED: match(2, "")}},
+				{{"game_1": match(1, "a")}, {"game_2": match(3, "b")}, {UNSCOPED: match(4, "")}},
+			},
+			expected: GameReport{
+				{"game_1": match(1, "a")},
+				{UNSCOPED: match(2, "")},
+				{"game_2": match(3, "b")},
+				{UNSCOPED: match(4, "")},
+			},
+		},
+		{
+			name: "Same fingerprint in a report",
+			reports: []GameReport{
+				{{"game_1": match(0, "a")}, {"game_2": match(0, "a")}},
+				{{"game_1": match(0, "a")}},
+			},
+			expected: GameReport{
+				{"game_1": match(0, "a")},
+				{"game_2": match(0, "a")},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, Merge(tc.reports...))
+		})
+	}
+}
+
+func TestTotals(t *testing.T) {
+	report := GameReport{
+		{"game_1": MatchReport{
+			TotalKills:   3,
+			Kills:        map[string]int{"Isgalamido (ID 2)": 2, "Mocinha (ID 3)": -1},
+			KillsByMeans: map[string]int{MOD_RAILGUN: 2, MOD_FALLING: 1},
+		}},
+		{"game_2": MatchReport{
+			TotalKills:   2,
+			Kills:        map[string]int{"Isgalamido (ID 4)": 1, "Zeh (ID 2)": 1},
+			KillsByMeans: map[string]int{MOD_RAILGUN: 1, MOD_SHOTGUN: 1},
+		}},
+		{UNSCOPED: MatchReport{
+			TotalKills:   1,
+			Kills:        map[string]int{"Zeh (ID 2)": 1},
+			KillsByMeans: map[string]int{MOD_RAILGUN: 1},
+		}},
+	}
+
+	assert.Equal(t, Totals{
+		Matches:      2,
+		TotalKills:   6,
+		Kills:        map[string]int{"Isgalamido": 3, "Mocinha": -1, "Zeh": 2},
+		KillsByMeans: map[string]int{MOD_RAILGUN: 4, MOD_FALLING: 1, MOD_SHOTGUN: 1},
+	}, report.Totals())
+	assert.Equal(t, Totals{Kills: map[string]int{}, KillsByMeans: map[string]int{}}, GameReport{}.Totals())
+}
@@ -0,0 +1,38 @@
+package quakelog
+
+// Option configures a Parser or ParseLines.
+type Option func(*gameState)
+
+// OnMatchEnd registers a function called with the report of every match as
+// soon as it ends, instead of waiting for the whole log to be parsed.
+func OnMatchEnd(handler func(map[string]MatchReport)) Option {
+	return func(game *gameState) {
+		game.onMatchEnd = handler
+	}
+}
+
+// OnEvent registers a function called with every decoded event and the
+// scoreboard of the current match right after the event was applied.
+func OnEvent(handler func(Event, Scoreboard)) Option {
+	return func(game *gameState) {
+		game.onEvent = handler
+	}
+}
+
+// OnDiagnostic registers a function called with every anomaly found in the
+// log, like a means of death whose name does not match its ID.
+func OnDiagnostic(handler func(Diagnostic)) Option {
+	return func(game *gameState) {
+		game.onDiagnostic = handler
+	}
+}
+
+// WithState resumes parsing from a snapshot taken by a previous run and stores
+// the final snapshot back into state once all lines are parsed. A match that
+// is still in progress is kept in the snapshot instead of being dropped.
+func WithState(state *State) Option {
+	return func(game *gameState) {
+		game.restore(*state)
+		game.state = state
+	}
+}
@@ -0,0 +1,61 @@
+package quakelog
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Orders of the players of the reports.
+const (
+	// ORDER_ID lists the players by client ID
+	ORDER_ID = "id"
+	// ORDER_NAME lists the players by name, then by client ID
+	ORDER_NAME = "name"
+	// ORDER_SCORE lists the players by score, highest first, then by client ID
+	ORDER_SCORE = "score"
+)
+
+// WithPlayerOrder sets the order of the players of the reports, ORDER_ID
+// being the default. Any other value is taken as ORDER_ID.
+func WithPlayerOrder(order string) Option {
+	return func(game *gameState) {
+		game.playerOrder = order
+	}
+}
+
+// SortPlayers sorts the players of match in order, like WithPlayerOrder. The
+// reports of the parser are already sorted, so it is only needed for the
+// reports changed afterwards.
+func SortPlayers(match *MatchReport, order string) {
+	players := match.Players
+	sort.SliceStable(players, func(i, j int) bool {
+		nameI, IDI := splitPlayer(players[i])
+		nameJ, IDJ := splitPlayer(players[j])
+
+		switch {
+		case order == ORDER_NAME && nameI != nameJ:
+			return nameI < nameJ
+		case order == ORDER_SCORE && match.Kills[players[i]] != match.Kills[players[j]]:
+			return match.Kills[players[i]] > match.Kills[players[j]]
+		case IDI != IDJ:
+			return IDI < IDJ
+		}
+		return nameI < nameJ
+	})
+}
+
+// splitPlayer splits a player of a report, formatted as "name (ID n)", into
+// its name and client ID, which is -1 when the player is not formatted so.
+func splitPlayer(player string) (string, int) {
+	i := strings.LastIndex(player, " (ID ")
+	if i < 0 || !strings.HasSuffix(player, ")") {
+		return player, -1
+	}
+
+	ID, err := strconv.Atoi(player[i+len(" (ID ") : len(player)-1])
+	if err != nil {
+		return player, -1
+	}
+	return player[:i], ID
+}
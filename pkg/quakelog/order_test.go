@@ -0,0 +1,79 @@
+package quakelog
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithPlayerOrder(t *testing.T) {
+	log := `  0:00 InitGame: \mapname\q3dm17
+  0:01 ClientUserinfoChanged: 12 n\Zeh\t\0
+  0:01 ClientUserinfoChanged: 3 n\Mocinha\t\0
+  0:01 ClientUserinfoChanged: 2 n\Zeh\t\0
+  0:01 ClientUserinfoChanged: 4 n\Assasinu Credi\t\0
+  0:02 Kill: 4 3 10: Assasinu Credi killed Mocinha by MOD_RAILGUN
+  0:03 Kill: 12 3 10: Zeh killed Mocinha by MOD_RAILGUN
+  0:04 Kill: 1022 2 22: <world> killed Zeh by MOD_TRIGGER_HURT
+  0:05 ShutdownGame:
+`
+
+	tests := []struct {
+		name     string
+		options  []Option
+		expected []string
+	}{
+		{
+			name:     "By ID by default",
+			expected: []string{"Zeh (ID 2)", "Mocinha (ID 3)", "Assasinu Credi (ID 4)", "Zeh (ID 12)"},
+		},
+		{
+			name:     "By ID",
+			options:  []Option{WithPlayerOrder(ORDER_ID)},
+			expected: []string{"Zeh (ID 2)", "Mocinha (ID 3)", "Assasinu Credi (ID 4)", "Zeh (ID 12)"},
+		},
+		{
+			name:     "By name, then by ID",
+			options:  []Option{WithPlayerOrder(ORDER_NAME)},
+			expected: []string{"Assasinu Credi (ID 4)", "Mocinha (ID 3)", "Zeh (ID 2)", "Zeh (ID 12)"},
+		},
+		{
+			name:     "By score, then by ID",
+			options:  []Option{WithPlayerOrder(ORDER_SCORE)},
+			expected: []string{"Assasinu Credi (ID 4)", "Zeh (ID 12)", "Mocinha (ID 3)", "Zeh (ID 2)"},
+		},
+		{
+			name:     "Unknown order",
+			options:  []Option{WithPlayerOrder("kills")},
+			expected: []string{"Zeh (ID 2)", "Mocinha (ID 3)", "Assasinu Credi (ID 4)", "Zeh (ID 12)"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			for i := 0; i < 10; i++ {
+				report, err := New(tc.options...).Parse(strings.NewReader(log))
+
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expected, report[0]["game_1"].Players, "The order is the same on every run")
+			}
+		})
+	}
+}
+
+func TestSortPlayers(t *testing.T) {
+	match := MatchReport{
+		Players: []string{"Zeh (ID 10)", "player", "Mocinha (ID 2)", "Dono da Bola (ID x)"},
+		Kills:   map[string]int{"Zeh (ID 10)": 3, "Mocinha (ID 2)": 3, "player": 5},
+	}
+
+	SortPlayers(&match, ORDER_ID)
+	assert.Equal(t, []string{"Dono da Bola (ID x)", "player", "Mocinha (ID 2)", "Zeh (ID 10)"}, match.Players, "Players without an ID come first")
+
+	SortPlayers(&match, ORDER_SCORE)
+	assert.Equal(t, []string{"player", "Mocinha (ID 2)", "Zeh (ID 10)", "Dono da Bola (ID x)"}, match.Players)
+
+	SortPlayers(&match, ORDER_NAME)
+	assert.Equal(t, []string{"Dono da Bola (ID x)", "Mocinha (ID 2)", "Zeh (ID 10)", "player"}, match.Players)
+}
@@ -0,0 +1,105 @@
+package quakelog
+
+// Policies for the events written outside a match, after its ShutdownGame or
+// before the first InitGame of the log.
+const (
+	// ORPHAN_DROP skips them, reporting each one as a DIAGNOSTIC_ORPHAN_EVENT
+	ORPHAN_DROP = "drop"
+	// ORPHAN_BUFFER keeps them in a report of their own, named UNSCOPED
+	ORPHAN_BUFFER = "buffer"
+	// ORPHAN_ATTACH applies them to the next match, right after its InitGame
+	ORPHAN_ATTACH = "attach"
+)
+
+// UNSCOPED names the report of the events buffered by ORPHAN_BUFFER. It is
+// added after the matches of the parsing, once any event was buffered.
+const UNSCOPED = "unscoped"
+
+// OrphanLine is a line written outside a match, kept by ORPHAN_ATTACH until
+// the next match starts.
+type OrphanLine struct {
+	Line int    `json:"line"`
+	Text string `json:"text"`
+}
+
+// WithOrphanPolicy sets how the events written outside a match are handled,
+// ORPHAN_DROP being the default. A ShutdownGame outside a match is always
+// dropped, so a repeated one does not report the match twice.
+func WithOrphanPolicy(policy string) Option {
+	return func(game *gameState) {
+		game.orphanPolicy = policy
+	}
+}
+
+// unscopedState holds the events buffered by ORPHAN_BUFFER, which are
+// applied to it like to a match.
+type unscopedState struct {
+	report  MatchReport
+	players map[int]*playerInfo
+}
+
+// orphan handles an event written outside a match.
+func (game *gameState) orphan(eventType string, matches []string) {
+	switch {
+	case eventType == END_GAME || game.orphanPolicy != ORPHAN_BUFFER && game.orphanPolicy != ORPHAN_ATTACH:
+		game.diagnose(DIAGNOSTIC_ORPHAN_EVENT, "%s outside a match", eventType)
+	case game.orphanPolicy == ORPHAN_ATTACH:
+		game.orphans = append(game.orphans, OrphanLine{Line: game.lineNumber, Text: game.line})
+	default:
+		game.processUnscoped(eventType, matches)
+	}
+}
+
+// attachOrphans applies the events kept by ORPHAN_ATTACH to the match that
+// just started, as if they were written right after its InitGame. Their
+// clients are not checked, since they were written before the clients joined
+// the match.
+func (game *gameState) attachOrphans() {
+	lineNumber, line, time := game.lineNumber, game.line, game.time
+	orphans := game.orphans
+	game.orphans = nil
+	game.attaching = true
+	defer func() { game.attaching = false }()
+
+	for _, orphan := range orphans {
+		game.lineNumber, game.line, game.time = orphan.Line, orphan.Text, parseTime(orphan.Text)
+		if eventType, matches := parseLogLine(orphan.Text); eventType != "" {
+			processEvent(eventType, matches, game)
+		}
+	}
+
+	game.lineNumber, game.line, game.time = lineNumber, line, time
+}
+
+// processUnscoped applies an event to the UNSCOPED report instead of the
+// match.
+func (game *gameState) processUnscoped(eventType string, matches []string) {
+	if game.unscoped == nil {
+		game.unscoped = &unscopedState{report: game.newMatchReport(""), players: make(map[int]*playerInfo)}
+	}
+
+	game.swapUnscoped()
+	processEvent(eventType, matches, game)
+	game.swapUnscoped()
+}
+
+// swapUnscoped swaps the state of the last match with the UNSCOPED one.
+func (game *gameState) swapUnscoped() {
+	unscoped := game.unscoped
+	game.inUnscoped = !game.inUnscoped
+	game.matchReport, unscoped.report = unscoped.report, game.matchReport
+	game.players, unscoped.players = unscoped.players, game.players
+}
+
+// unscopedReport returns the UNSCOPED report, if any event was buffered.
+func (game *gameState) unscopedReport() (map[string]MatchReport, bool) {
+	if game.unscoped == nil {
+		return nil, false
+	}
+
+	game.swapUnscoped()
+	defer game.swapUnscoped()
+	game.tallyPlayers()
+
+	return map[string]MatchReport{UNSCOPED: game.matchReport}, true
+}
@@ -0,0 +1,157 @@
+package quakelog
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// orphansLog has a kill before the first match, a kill and a user info
+// change between the two matches, and a repeated ShutdownGame.
+const orphansLog = `  0:00 Kill: 1022 2 22: <world> killed Player1 by MOD_TRIGGER_HURT
+  0:00 InitGame: \mapname\q3dm17
+  0:01 ClientUserinfoChanged: 2 n\Player1\t\0
+  0:02 Kill: 1022 2 22: <world> killed Player1 by MOD_TRIGGER_HURT
+  0:03 ShutdownGame:
+  0:03 ShutdownGame:
+  0:04 ClientUserinfoChanged: 3 n\Player2\t\0
+  0:04 Kill: 3 2 10: Player2 killed Player1 by MOD_RAILGUN
+  0:00 InitGame: \mapname\q3dm6
+  0:01 ClientUserinfoChanged: 2 n\Player1\t\0
+  0:05 ShutdownGame:
+`
+
+func TestOrphanPolicies(t *testing.T) {
+	tests := []struct {
+		name        string
+		options     []Option
+		expected    []map[string]map[string]int
+		diagnostics []int
+	}{
+		{
+			name: "Dropped by default",
+			expected: []map[string]map[string]int{
+				{"game_1": {"Player1 (ID 2)": -1}},
+				{"game_2": {"Player1 (ID 2)": 0}},
+			},
+			diagnostics: []int{1, 6, 7, 8},
+		},
+		{
+			name:    "Dropped",
+			options: []Option{WithOrphanPolicy(ORPHAN_DROP)},
+			expected: []map[string]map[string]int{
+				{"game_1": {"Player1 (ID 2)": -1}},
+				{"game_2": {"Player1 (ID 2)": 0}},
+			},
+			diagnostics: []int{1, 6, 7, 8},
+		},
+		{
+			name:    "Buffered",
+			options: []Option{WithOrphanPolicy(ORPHAN_BUFFER)},
+			expected: []map[string]map[string]int{
+				{"game_1": {"Player1 (ID 2)": -1}},
+				{"game_2": {"Player1 (ID 2)": 0}},
+				{UNSCOPED: {"Player1 (ID 2)": -1, "Player2 (ID 3)": 1}},
+			},
+			diagnostics: []int{6},
+		},
+		{
+			name:    "Attached to the next match",
+			options: []Option{WithOrphanPolicy(ORPHAN_ATTACH)},
+			expected: []map[string]map[string]int{
+				{"game_1": {"Player1 (ID 2)": -2}},
+				{"game_2": {"Player1 (ID 2)": 0, "Player2 (ID 3)": 1}},
+			},
+			diagnostics: []int{6},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			diagnostics := &Diagnostics{}
+			report, err := New(append(tc.options, OnDiagnostic(diagnostics.Add))...).Parse(strings.NewReader(orphansLog))
+
+			assert.NoError(t, err)
+			kills := make([]map[string]map[string]int, 0, len(report))
+			for _, match := range report {
+				for name, game := range match {
+					kills = append(kills, map[string]map[string]int{name: game.Kills})
+				}
+			}
+			assert.Equal(t, tc.expected, kills)
+
+			lines := make([]int, 0)
+			for _, diagnostic := range diagnostics.List() {
+				assert.Equal(t, DIAGNOSTIC_ORPHAN_EVENT, diagnostic.Kind)
+				lines = append(lines, diagnostic.Line)
+			}
+			assert.Equal(t, tc.diagnostics, lines, "A repeated ShutdownGame is always dropped")
+		})
+	}
+}
+
+func TestOrphanEvents(t *testing.T) {
+	t.Run("Buffered", func(t *testing.T) {
+		games := make([]string, 0)
+		_, err := New(WithOrphanPolicy(ORPHAN_BUFFER), OnEvent(func(event Event, _ Scoreboard) {
+			if event.Type == EVENT_KILL {
+				games = append(games, event.Game)
+			}
+		})).Parse(strings.NewReader(orphansLog))
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{UNSCOPED, "game_1", UNSCOPED}, games)
+	})
+
+	t.Run("Attached", func(t *testing.T) {
+		events := make([]string, 0)
+		_, err := New(WithOrphanPolicy(ORPHAN_ATTACH), OnEvent(func(event Event, _ Scoreboard) {
+			events = append(events, event.Game+" "+event.Time+" "+event.Type)
+		})).Parse(strings.NewReader(orphansLog))
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{
+			"game_1 0:00 match_start",
+			"game_1 0:00 kill",
+			"game_1 0:01 player_info",
+			"game_1 0:02 kill",
+			"game_1 0:03 match_end",
+			"game_2 0:00 match_start",
+			"game_2 0:04 player_info",
+			"game_2 0:04 kill",
+			"game_2 0:01 player_info",
+			"game_2 0:05 match_end",
+		}, events, "The attached events keep their time")
+	})
+}
+
+func TestOrphansResumed(t *testing.T) {
+	lines := strings.SplitAfter(orphansLog, "\n")
+	state := &State{}
+
+	first, err := New(WithOrphanPolicy(ORPHAN_ATTACH), WithState(state)).Parse(strings.NewReader(strings.Join(lines[:8], "")))
+	assert.NoError(t, err)
+	assert.Len(t, first, 1)
+	assert.Len(t, state.Orphans, 2, "The events waiting for the next match are kept in the state")
+
+	second, err := New(WithOrphanPolicy(ORPHAN_ATTACH), WithState(state)).Parse(strings.NewReader(strings.Join(lines[8:], "")))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"Player1 (ID 2)": 0, "Player2 (ID 3)": 1}, second[0]["game_2"].Kills)
+	assert.Empty(t, state.Orphans)
+}
+
+func TestOrphansConcurrently(t *testing.T) {
+	for _, policy := range []string{ORPHAN_BUFFER, ORPHAN_ATTACH} {
+		_, err := New(WithOrphanPolicy(policy)).ParseConcurrently(context.Background(), strings.NewReader(orphansLog), int64(len(orphansLog)), 2)
+		assert.EqualError(t, err, "orphan policy "+policy+" cannot be used when parsing concurrently")
+	}
+
+	concurrent, err := New().ParseConcurrently(context.Background(), strings.NewReader(orphansLog), int64(len(orphansLog)), 2)
+	assert.NoError(t, err)
+
+	sequential, err := New().Parse(strings.NewReader(orphansLog))
+	assert.NoError(t, err)
+	assert.Equal(t, sequential, concurrent)
+}
@@ -0,0 +1,341 @@
+package quakelog
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// ParseLines parses the lines received from lines until the channel is
+// closed, then sends the report of every finished match to gameReport and
+// closes it. It is the channel based counterpart of Parser.Parse.
+//
+// When ctx is done ParseLines gives up: it closes gameReport without sending
+// the report, so neither a blocked producer nor a missing consumer can keep it
+// running. It also gives up in Strict mode at the first anomaly, which is sent
+// to errChan as a *DiagnosticError; errChan may be nil otherwise.
+func ParseLines(ctx context.Context, lines <-chan string, gameReport chan<- GameReport, errChan chan<- error, options ...Option) {
+	defer close(gameReport)
+
+	game := newGameState(options)
+
+	for {
+		select {
+		case line, ok := <-lines:
+			if !ok {
+				select {
+				case gameReport <- game.finish():
+				case <-ctx.Done():
+				}
+				return
+			}
+			game.parseLine(line)
+			if game.err != nil {
+				select {
+				case errChan <- game.err:
+				case <-ctx.Done():
+				}
+				return
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func newGameState(options []Option) *gameState {
+	game := &gameState{
+		totalGames:  0,
+		gameStarted: false,
+		players:     make(map[int]*playerInfo),
+		matchReport: MatchReport{},
+		gameReport:  make(GameReport, 0),
+		scoring:     DefaultScoringRules(),
+		means:       DefaultMeansRegistry(),
+		ignored:     ignoredEntries,
+	}
+	for _, option := range options {
+		option(game)
+	}
+	if game.gameStarted {
+		game.activeHandlers()
+	}
+
+	return game
+}
+
+func (game *gameState) parseLine(line string) {
+	game.lineNumber++
+	game.line = line
+	game.time = parseTime(line)
+
+	if entry, ok := game.spliced(line); ok {
+		game.diagnose(DIAGNOSTIC_SPLICED_LINE, "line cut off by the next entry")
+		// the entry glued to the line is complete, so it is parsed on its own
+		game.lineNumber--
+		game.parseLine(entry)
+		return
+	}
+
+	eventType, matches := parseLogLine(line)
+	game.checkClock(line, eventType)
+	if eventType == "" || matches == nil {
+		game.checkEntry(line)
+		return
+	}
+	processEvent(eventType, matches, game)
+	game.fingerprint(eventType)
+}
+
+func (game *gameState) finish() GameReport {
+	if game.state != nil {
+		*game.state = game.snapshot()
+	}
+	if report, ok := game.unscopedReport(); ok {
+		game.gameReport = append(game.gameReport, report)
+	}
+
+	return game.gameReport
+}
+
+// parseLogLine finds the event of line. A line naming a known entry is only
+// matched against the pattern of that entry, and any other line against the
+// patterns in eventOrder, so the same line is always decoded the same way.
+func parseLogLine(line string) (eventType string, matches []string) {
+	if entry := LineEntry.FindStringSubmatch(line); entry != nil && RegexPatterns[entry[2]] != nil {
+		if matches := RegexPatterns[entry[2]].FindStringSubmatch(line); matches != nil {
+			return entry[2], matches
+		}
+		return "", nil
+	}
+
+	for _, eventType := range eventOrder {
+		if matches := RegexPatterns[eventType].FindStringSubmatch(line); matches != nil {
+			return eventType, matches
+		}
+	}
+
+	return "", nil
+}
+
+func parseTime(line string) string {
+	if matches := LineTime.FindStringSubmatch(line); matches != nil {
+		return matches[1]
+	}
+
+	return ""
+}
+
+func processEvent(eventType string, matches []string, game *gameState) {
+	if !game.gameStarted && !game.inUnscoped && eventType != INIT_GAME {
+		game.orphan(eventType, matches)
+		return
+	}
+
+	switch eventType {
+	case INIT_GAME:
+		if game.gameStarted {
+			game.closeUnfinished()
+		}
+
+		game.gameStarted = true
+		game.initGame(parseSettings(matches[1]))
+		game.emit(Event{Type: EVENT_MATCH_START})
+		game.attachOrphans()
+	case KILL:
+		/*
+			^.*Kill: (\d+) (\d+) (\d+): (.+) killed (.+) by (.+)$
+			(\d+) = killerID
+			(\d+) = victimID
+			(\d+) = methodID
+			(.+) = killerName
+			(.+?) = victimName
+			(\S*) = method, when present
+		*/
+		IDs, ok := game.atoi(matches[1], matches[2], matches[3])
+		if !ok {
+			return
+		}
+		killerID, victimID, methodID := IDs[0], IDs[1], IDs[2]
+		killerName := matches[4]
+		victimName := matches[5]
+		if victimID == WORLD_ID {
+			game.diagnose(DIAGNOSTIC_MALFORMED_LINE, "kill of %s", WORLD)
+			return
+		}
+		method := game.resolveMeans(methodID, matches[6])
+
+		game.checkClients(killerID, victimID)
+		means, _ := game.means.ByName(method)
+		teamKill := game.handlePlayerKill(killerName, victimName, killerID, victimID, method)
+		game.handleKillsByMeans(method)
+		game.emit(Event{Type: EVENT_KILL, Kill: &KillEvent{
+			KillerID: killerID,
+			Killer:   killerName,
+			VictimID: victimID,
+			Victim:   victimName,
+			Method:   method,
+			MethodID: methodID,
+			Category: means.Category,
+			Label:    means.Label,
+			TeamKill: teamKill,
+		}})
+
+	case USER_INFO:
+		/*
+			(\d+) n\\([^\\]+)\\t(?:\\(\d+))?
+			(\d+) = playerID
+			n\\([^\\]+)\\t = playerName (extracted from n\playerName\t)
+			(\d+) = team, when present
+		*/
+		fields := []string{matches[1]}
+		if matches[3] != "" {
+			fields = append(fields, matches[3])
+		}
+		IDs, ok := game.atoi(fields...)
+		if !ok {
+			return
+		}
+		playerID, playerName, team := IDs[0], matches[2], TEAM_FREE
+		if len(IDs) > 1 {
+			team = IDs[1]
+		}
+		game.updateUserInfo(playerID, playerName, team)
+		game.emit(Event{Type: EVENT_PLAYER_INFO, Player: &PlayerEvent{ID: playerID, Name: playerName}})
+
+	case CLIENT_CONNECT, CLIENT_DISCONNECT:
+		/*
+			(\d+) = playerID
+		*/
+		IDs, ok := game.atoi(matches[1])
+		if !ok {
+			return
+		}
+		playerID := IDs[0]
+		event := Event{Type: EVENT_JOIN, Player: &PlayerEvent{ID: playerID}}
+		if eventType == CLIENT_DISCONNECT {
+			event.Type = EVENT_LEAVE
+		}
+		if player, ok := game.players[playerID]; ok {
+			event.Player.Name = player.name
+		}
+		game.emit(event)
+
+	case END_GAME:
+		game.gameStarted = false
+		game.endGame()
+	}
+}
+
+func (game *gameState) initGame(settings map[string]string) {
+	game.lastTime = game.time
+	game.players = make(map[int]*playerInfo)
+	game.teamGame = isTeamGameType(settings["g_gametype"])
+	game.diagnostics = nil
+	game.totalGames++
+	game.matchReport = game.newMatchReport(settings["mapname"])
+}
+
+// newMatchReport returns the report of a match without kills, listing every
+// means of death of the registry.
+func (game *gameState) newMatchReport(mapName string) MatchReport {
+	report := MatchReport{
+		Map:          mapName,
+		TotalKills:   0,
+		Players:      make([]string, 0),
+		Kills:        make(map[string]int),
+		KillsByMeans: make(map[string]int),
+	}
+	for _, means := range game.means.Means() {
+		report.KillsByMeans[means.Name] = 0
+	}
+
+	return report
+}
+
+// parseSettings splits the InitGame server settings, formatted as
+// \key1\value1\key2\value2, into a map.
+func parseSettings(settings string) map[string]string {
+	fields := strings.Split(strings.TrimPrefix(settings, "\\"), "\\")
+	parsed := make(map[string]string, len(fields)/2)
+	for i := 0; i+1 < len(fields); i += 2 {
+		parsed[fields[i]] = fields[i+1]
+	}
+
+	return parsed
+}
+
+func (game *gameState) endGame() {
+	game.tallyPlayers()
+	game.matchReport.Sections = game.endHandlers()
+	if teamKills := game.teamKills(); game.teamGame && len(teamKills) > 0 {
+		if game.matchReport.Sections == nil {
+			game.matchReport.Sections = make(map[string]any)
+		}
+		game.matchReport.Sections[SECTION_TEAM_KILLS] = teamKills
+	}
+	if game.reportDiagnostics && len(game.diagnostics) > 0 {
+		if game.matchReport.Sections == nil {
+			game.matchReport.Sections = make(map[string]any)
+		}
+		game.matchReport.Sections[SECTION_DIAGNOSTICS] = game.diagnostics
+	}
+
+	gameName := game.gameName()
+	report := make(map[string]MatchReport)
+	report[gameName] = game.matchReport
+	game.gameReport = append(game.gameReport, report)
+
+	if game.onMatchEnd != nil {
+		game.onMatchEnd(report)
+	}
+
+	match := game.matchReport
+	game.emit(Event{Type: EVENT_MATCH_END, Match: &match})
+
+	game.players = nil
+}
+
+// tallyPlayers adds the players of the match and their score to its report,
+// in the order set by WithPlayerOrder.
+func (game *gameState) tallyPlayers() {
+	for ID, player := range game.players {
+		playerName := fmt.Sprintf("%s (ID %d)", player.name, ID)
+		if _, ok := game.matchReport.Kills[playerName]; !ok {
+			game.matchReport.Kills[playerName] = 0
+			game.matchReport.Players = append(game.matchReport.Players, playerName)
+		}
+		game.matchReport.Kills[playerName] += player.kills
+	}
+	SortPlayers(&game.matchReport, game.playerOrder)
+}
+
+func (game *gameState) handlePlayerKill(killerName, victimName string, killerID, victimID int, method string) bool {
+	killer := game.resolve(killerID, killerName)
+	victim := game.resolve(victimID, victimName)
+
+	teamKill := game.scoreKill(killer, victim, method)
+
+	game.matchReport.TotalKills += 1
+	return teamKill
+}
+
+func (game *gameState) updateUserInfo(playerID int, playerName string, team int) {
+	if _, ok := game.players[playerID]; !ok {
+		game.players[playerID] = &playerInfo{
+			name:  playerName,
+			kills: 0,
+			team:  team,
+		}
+	} else {
+		game.players[playerID].name = playerName
+		game.players[playerID].team = team
+	}
+}
+
+func (game *gameState) handleKillsByMeans(method string) {
+	if _, ok := game.matchReport.KillsByMeans[method]; !ok {
+		game.matchReport.KillsByMeans[method] = 0
+	}
+	game.matchReport.KillsByMeans[method] += 1
+}
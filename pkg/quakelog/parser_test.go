@@ -0,0 +1,483 @@
+package quakelog
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var defaultKillsByMeans = map[string]int{
+	MOD_UNKNOWN:        0,
+	MOD_SHOTGUN:        0,
+	MOD_GAUNTLET:       0,
+	MOD_MACHINEGUN:     0,
+	MOD_GRENADE:        0,
+	MOD_GRENADE_SPLASH: 0,
+	MOD_ROCKET:         0,
+	MOD_ROCKET_SPLASH:  0,
+	MOD_PLASMA:         0,
+	MOD_PLASMA_SPLASH:  0,
+	MOD_RAILGUN:        0,
+	MOD_LIGHTNING:      0,
+	MOD_BFG:            0,
+	MOD_BFG_SPLASH:     0,
+	MOD_WATER:          0,
+	MOD_SLIME:          0,
+	MOD_LAVA:           0,
+	MOD_CRUSH:          0,
+	MOD_TELEFRAG:       0,
+	MOD_FALLING:        0,
+	MOD_SUICIDE:        0,
+	MOD_TARGET_LASER:   0,
+	MOD_TRIGGER_HURT:   0,
+	MOD_NAIL:           0,
+	MOD_CHAINGUN:       0,
+	MOD_PROXIMITY_MINE: 0,
+	MOD_KAMIKAZE:       0,
+	MOD_JUICED:         0,
+	MOD_GRAPPLE:        0,
+}
+
+func TestParseLines(t *testing.T) {
+	tests := []struct {
+		name     string
+		lines    []string
+		expected GameReport
+	}{
+		{
+			name: "Simple game with one kill",
+			lines: []string{
+				"0:00 InitGame: \\sv_floodProtect\\1",
+				"0:01 ClientUserinfoChanged: 2 n\\Player1\\t\\0",
+				"0:01 ClientUserinfoChanged: 3 n\\Player2\\t\\0",
+				"0:02 Kill: 2 3 7: Player1 killed Player2 by MOD_ROCKET",
+				"0:03 ShutdownGame:",
+			},
+			expected: GameReport{
+				{
+					"game_1": MatchReport{
+						TotalKills: 1,
+						Players:    []string{"Player1 (ID 2)", "Player2 (ID 3)"},
+						Kills: map[string]int{
+							"Player1 (ID 2)": 1,
+							"Player2 (ID 3)": 0,
+						},
+						KillsByMeans: func() map[string]int {
+							kills := copyKillsByMeans(defaultKillsByMeans)
+							kills[MOD_ROCKET] = 1
+							return kills
+						}(),
+					},
+				},
+			},
+		},
+		{
+			name: "Game with world kill and suicide",
+			lines: []string{
+				"0:00 InitGame: \\sv_floodProtect\\1",
+				"0:01 ClientUserinfoChanged: 2 n\\Player1\\t\\0",
+				"0:02 Kill: 1022 2 22: <world> killed Player1 by MOD_TRIGGER_HURT",
+				"0:03 Kill: 2 2 7: Player1 killed Player1 by MOD_ROCKET",
+				"0:04 ShutdownGame:",
+			},
+			expected: GameReport{
+				{
+					"game_1": MatchReport{
+						TotalKills: 2,
+						Players:    []string{"Player1 (ID 2)"},
+						Kills: map[string]int{
+							"Player1 (ID 2)": -2,
+						},
+						KillsByMeans: func() map[string]int {
+							kills := copyKillsByMeans(defaultKillsByMeans)
+							kills[MOD_TRIGGER_HURT] = 1
+							kills[MOD_ROCKET] = 1
+							return kills
+						}(),
+					},
+				},
+			},
+		},
+		{
+			name: "Multiple games",
+			lines: []string{
+				"0:00 InitGame: \\sv_floodProtect\\1",
+				"0:01 ClientUserinfoChanged: 2 n\\Player1\\t\\0",
+				"0:01 ClientUserinfoChanged: 4 n\\Player2\\t\\0",
+				"0:02 Kill: 2 4 7: Player1 killed Player2 by MOD_ROCKET",
+				"0:03 ShutdownGame:",
+				"0:04 InitGame: \\sv_floodProtect\\1",
+				"0:05 ClientUserinfoChanged: 2 n\\Player1\\t\\0",
+				"0:05 ClientUserinfoChanged: 4 n\\Player3\\t\\0",
+				"0:06 Kill: 2 4 8: Player1 killed Player3 by MOD_ROCKET_SPLASH",
+				"0:07 ShutdownGame:",
+			},
+			expected: GameReport{
+				{
+					"game_1": MatchReport{
+						TotalKills: 1,
+						Players:    []string{"Player1 (ID 2)", "Player2 (ID 4)"},
+						Kills: map[string]int{
+							"Player1 (ID 2)": 1,
+							"Player2 (ID 4)": 0,
+						},
+						KillsByMeans: func() map[string]int {
+							kills := copyKillsByMeans(defaultKillsByMeans)
+							kills[MOD_ROCKET] = 1
+							return kills
+						}(),
+					},
+				},
+				{
+					"game_2": MatchReport{
+						TotalKills: 1,
+						Players:    []string{"Player1 (ID 2)", "Player3 (ID 4)"},
+						Kills: map[string]int{
+							"Player1 (ID 2)": 1,
+							"Player3 (ID 4)": 0,
+						},
+						KillsByMeans: func() map[string]int {
+							kills := copyKillsByMeans(defaultKillsByMeans)
+							kills[MOD_ROCKET_SPLASH] = 1
+							return kills
+						}(),
+					},
+				},
+			},
+		},
+		{
+			name: "Two inits without shutdown",
+			lines: []string{
+				"0:00 InitGame: \\sv_floodProtect\\1",
+				"0:01 ClientUserinfoChanged: 2 n\\Player1\\t\\0",
+				"0:01 ClientUserinfoChanged: 3 n\\Player2\\t\\0",
+				"0:02 Kill: 2 3 7: Player1 killed Player2 by MOD_ROCKET",
+				"0:03 InitGame: \\sv_floodProtect\\1",
+				"0:04 ClientUserinfoChanged: 2 n\\Player1\\t\\0",
+				"0:01 ClientUserinfoChanged: 4 n\\Player3\\t\\0",
+				"0:05 Kill: 2 4 7: Player1 killed Player3 by MOD_ROCKET_SPLASH",
+				"0:06 ShutdownGame:",
+			},
+			expected: GameReport{
+				{
+					"game_1": MatchReport{
+						TotalKills: 1,
+						Players:    []string{"Player1 (ID 2)", "Player2 (ID 3)"},
+						Kills: map[string]int{
+							"Player1 (ID 2)": 1,
+							"Player2 (ID 3)": 0,
+						},
+						KillsByMeans: func() map[string]int {
+							kills := copyKillsByMeans(defaultKillsByMeans)
+							kills[MOD_ROCKET] = 1
+							return kills
+						}(),
+						Integrity: &Integrity{
+							Status: INTEGRITY_INCOMPLETE,
+							Issues: []Diagnostic{{
+								Line:   5,
+								Game:   "game_1",
+								Time:   "0:03",
+								Kind:   DIAGNOSTIC_MISSING_SHUTDOWN,
+								Reason: "match ended without ShutdownGame",
+								Text:   "0:03 InitGame: \\sv_floodProtect\\1",
+							}},
+						},
+					},
+				},
+				{
+					"game_2": MatchReport{
+						TotalKills: 1,
+						Players:    []string{"Player1 (ID 2)", "Player3 (ID 4)"},
+						Kills: map[string]int{
+							"Player1 (ID 2)": 1,
+							"Player3 (ID 4)": 0,
+						},
+						KillsByMeans: func() map[string]int {
+							kills := copyKillsByMeans(defaultKillsByMeans)
+							kills[MOD_ROCKET_SPLASH] = 1
+							return kills
+						}(),
+						Integrity: &Integrity{
+							Status: INTEGRITY_CORRUPTED,
+							Issues: []Diagnostic{{
+								Line:   7,
+								Game:   "game_2",
+								Time:   "0:01",
+								Kind:   DIAGNOSTIC_CLOCK_JUMP,
+								Reason: "time went back from 0:04",
+								Text:   "0:01 ClientUserinfoChanged: 4 n\\Player3\\t\\0",
+							}},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			lines := make(chan string)
+			gameReport := make(chan GameReport)
+
+			go func() {
+				for _, line := range tc.lines {
+					lines <- line
+				}
+				close(lines)
+			}()
+
+			go ParseLines(context.Background(), lines, gameReport, nil)
+
+			result := <-gameReport
+
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func TestParseLinesOnMatchEnd(t *testing.T) {
+	lines := make(chan string)
+	gameReport := make(chan GameReport)
+	matches := make([]map[string]MatchReport, 0)
+
+	go func() {
+		for _, line := range []string{
+			"0:00 InitGame: \\sv_floodProtect\\1",
+			"0:01 ClientUserinfoChanged: 2 n\\Player1\\t\\0",
+			"0:02 Kill: 1022 2 22: <world> killed Player1 by MOD_TRIGGER_HURT",
+			"0:03 ShutdownGame:",
+			"0:04 InitGame: \\sv_floodProtect\\1",
+		} {
+			lines <- line
+		}
+		close(lines)
+	}()
+
+	go ParseLines(context.Background(), lines, gameReport, nil, OnMatchEnd(func(match map[string]MatchReport) {
+		matches = append(matches, match)
+	}))
+
+	result := <-gameReport
+
+	assert.Len(t, matches, 1, "Only finished matches are reported")
+	assert.Equal(t, result[0], matches[0])
+	assert.Equal(t, -1, matches[0]["game_1"].Kills["Player1 (ID 2)"])
+}
+
+func TestParseLinesWithState(t *testing.T) {
+	parse := func(lines []string, state *State) GameReport {
+		linesChan := make(chan string)
+		gameReport := make(chan GameReport)
+
+		go func() {
+			for _, line := range lines {
+				linesChan <- line
+			}
+			close(linesChan)
+		}()
+
+		go ParseLines(context.Background(), linesChan, gameReport, nil, WithState(state))
+
+		return <-gameReport
+	}
+
+	state := &State{}
+	first := parse([]string{
+		"0:00 InitGame: \\sv_floodProtect\\1",
+		"0:01 ShutdownGame:",
+		"0:02 InitGame: \\sv_floodProtect\\1",
+		"0:03 ClientUserinfoChanged: 2 n\\Player1\\t\\0",
+		"0:04 Kill: 2 3 7: Player1 killed Player2 by MOD_ROCKET",
+	}, state)
+
+	assert.Len(t, first, 1, "The match in progress is not reported")
+	assert.Equal(t, 2, state.TotalGames)
+	assert.True(t, state.GameStarted)
+	assert.Equal(t, PlayerState{Name: "Player1", Kills: 1}, state.Players[2])
+
+	second := parse([]string{
+		"0:05 Kill: 3 2 7: Player2 killed Player1 by MOD_ROCKET",
+		"0:06 ShutdownGame:",
+	}, state)
+
+	assert.Len(t, second, 1)
+	match := second[0]["game_2"]
+	assert.Equal(t, 2, match.TotalKills)
+	assert.Equal(t, map[string]int{"Player1 (ID 2)": 1, "Player2 (ID 3)": 1}, match.Kills)
+	assert.Equal(t, 2, match.KillsByMeans[MOD_ROCKET])
+	assert.Equal(t, State{TotalGames: 2, Lines: 7}, *state, "The lines are counted across runs")
+}
+
+func TestParseLinesOnEvent(t *testing.T) {
+	lines := make(chan string)
+	gameReport := make(chan GameReport)
+	events := make([]Event, 0)
+	scoreboards := make([]Scoreboard, 0)
+
+	go func() {
+		for _, line := range []string{
+			"0:00 InitGame: \\mapname\\q3dm17",
+			"0:01 ClientConnect: 2",
+			"0:01 ClientUserinfoChanged: 2 n\\Player1\\t\\0",
+			"0:01 ClientConnect: 3",
+			"0:01 ClientUserinfoChanged: 3 n\\Player2\\t\\0",
+			"0:02 Item: 2 weapon_railgun",
+			"0:03 Kill: 2 3 10: Player1 killed Player2 by MOD_RAILGUN",
+			"0:04 ClientDisconnect: 3",
+			"0:05 ShutdownGame:",
+		} {
+			lines <- line
+		}
+		close(lines)
+	}()
+
+	go ParseLines(context.Background(), lines, gameReport, nil, OnEvent(func(event Event, scoreboard Scoreboard) {
+		events = append(events, event)
+		scoreboards = append(scoreboards, scoreboard)
+	}))
+
+	result := <-gameReport
+
+	assert.Equal(t, []Event{
+		{Type: EVENT_MATCH_START, Time: "0:00", Game: "game_1"},
+		{Type: EVENT_JOIN, Time: "0:01", Game: "game_1", Player: &PlayerEvent{ID: 2}},
+		{Type: EVENT_PLAYER_INFO, Time: "0:01", Game: "game_1", Player: &PlayerEvent{ID: 2, Name: "Player1"}},
+		{Type: EVENT_JOIN, Time: "0:01", Game: "game_1", Player: &PlayerEvent{ID: 3}},
+		{Type: EVENT_PLAYER_INFO, Time: "0:01", Game: "game_1", Player: &PlayerEvent{ID: 3, Name: "Player2"}},
+		{Type: EVENT_KILL, Time: "0:03", Game: "game_1", Kill: &KillEvent{
+			KillerID: 2, Killer: "Player1", VictimID: 3, Victim: "Player2", Method: MOD_RAILGUN,
+			MethodID: 10, Category: CATEGORY_WEAPON, Label: "Railgun",
+		}},
+		{Type: EVENT_LEAVE, Time: "0:04", Game: "game_1", Player: &PlayerEvent{ID: 3, Name: "Player2"}},
+		{Type: EVENT_MATCH_END, Time: "0:05", Game: "game_1", Match: func() *MatchReport {
+			match := result[0]["game_1"]
+			return &match
+		}()},
+	}, events)
+
+	assert.Equal(t, Scoreboard{
+		Game:       "game_1",
+		Map:        "q3dm17",
+		InProgress: true,
+		TotalKills: 1,
+		Players: []ScoreboardEntry{
+			{ID: 2, Name: "Player1", Kills: 1},
+			{ID: 3, Name: "Player2", Kills: 0},
+		},
+	}, scoreboards[5])
+	assert.False(t, scoreboards[7].InProgress)
+}
+
+func TestParseSettings(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings string
+		expected map[string]string
+	}{
+		{
+			name:     "Server settings",
+			settings: "\\sv_floodProtect\\1\\mapname\\q3dm17\\g_needpass\\0",
+			expected: map[string]string{"sv_floodProtect": "1", "mapname": "q3dm17", "g_needpass": "0"},
+		},
+		{
+			name:     "Key without value",
+			settings: "\\mapname\\q3dm17\\g_needpass",
+			expected: map[string]string{"mapname": "q3dm17"},
+		},
+		{
+			name:     "Empty settings",
+			settings: "",
+			expected: map[string]string{},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, parseSettings(tc.settings))
+		})
+	}
+}
+
+func TestParseLogLineAmbiguous(t *testing.T) {
+	tests := []struct {
+		name      string
+		line      string
+		eventType string
+	}{
+		{
+			name:      "Kill of a player named after another entry",
+			line:      "  0:05 Kill: 2 3 7: InitGame: killed ShutdownGame: by MOD_ROCKET_SPLASH",
+			eventType: KILL,
+		},
+		{
+			name:      "Malformed entry containing another entry",
+			line:      "  0:05 Kill: InitGame: \\mapname\\q3dm17",
+			eventType: "",
+		},
+		{
+			name:      "Line without entry matching several patterns",
+			line:      "2InitGame: Kill: 0 7 7: Y killed 01",
+			eventType: INIT_GAME,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// the patterns used to be tried in map order, which changes between runs
+			for range 20 {
+				eventType, _ := parseLogLine(tc.line)
+				assert.Equal(t, tc.eventType, eventType)
+			}
+		})
+	}
+}
+
+func copyKillsByMeans(original map[string]int) map[string]int {
+	copy := make(map[string]int)
+	for k, v := range original {
+		copy[k] = v
+	}
+	return copy
+}
+
+func TestParseLinesCancel(t *testing.T) {
+	t.Run("Producer never closes lines", func(t *testing.T) {
+		lines := make(chan string)
+		gameReport := make(chan GameReport)
+		ctx, cancel := context.WithCancel(context.Background())
+
+		go ParseLines(ctx, lines, gameReport, nil)
+		lines <- "  0:00 InitGame: \\mapname\\q3dm17"
+		cancel()
+
+		select {
+		case _, open := <-gameReport:
+			assert.False(t, open, "No report is sent once cancelled")
+		case <-time.After(time.Second):
+			t.Fatal("ParseLines did not stop")
+		}
+	})
+
+	t.Run("Nobody receives the report", func(t *testing.T) {
+		lines := make(chan string)
+		gameReport := make(chan GameReport)
+		ctx, cancel := context.WithCancel(context.Background())
+		finished := make(chan struct{})
+
+		go func() {
+			ParseLines(ctx, lines, gameReport, nil)
+			close(finished)
+		}()
+		close(lines)
+		cancel()
+
+		select {
+		case <-finished:
+		case <-time.After(time.Second):
+			t.Fatal("ParseLines did not stop")
+		}
+	})
+}
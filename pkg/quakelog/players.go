@@ -0,0 +1,42 @@
+package quakelog
+
+// WORLD_ID is the client ID of WORLD in Kill lines.
+const WORLD_ID = 1022
+
+// UNKNOWN_PLAYER names the clients only known by their ID, when the Kill line
+// does not give a name usable for them.
+const UNKNOWN_PLAYER = "<unknown>"
+
+// entity is the killer or the victim of a kill: WORLD or a player of the
+// match in progress.
+type entity struct {
+	ID     int
+	player *playerInfo
+}
+
+func (e entity) isWorld() bool {
+	return e.player == nil
+}
+
+// resolve returns the entity of a client ID, which decides whether it is
+// WORLD whatever the name written in the Kill line. The clients the match
+// has not seen yet are registered with that name.
+func (game *gameState) resolve(ID int, name string) entity {
+	if ID == WORLD_ID {
+		return entity{ID: ID}
+	}
+
+	player, ok := game.players[ID]
+	if !ok {
+		if name == WORLD || name == "" {
+			name = UNKNOWN_PLAYER
+		}
+		player = &playerInfo{name: name}
+		if game.players == nil {
+			game.players = make(map[int]*playerInfo)
+		}
+		game.players[ID] = player
+	}
+
+	return entity{ID: ID, player: player}
+}
@@ -0,0 +1,149 @@
+package quakelog
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKillEntities(t *testing.T) {
+	tests := []struct {
+		name        string
+		kill        string
+		kills       map[string]int
+		totalKills  int
+		diagnostics []string
+	}{
+		{
+			name:       "World kills a player",
+			kill:       "Kill: 1022 3 22: <world> killed Player2 by MOD_TRIGGER_HURT",
+			kills:      map[string]int{"Player1 (ID 2)": 0, "Player2 (ID 3)": -1},
+			totalKills: 1,
+		},
+		{
+			name:        "World kills an unknown client",
+			kill:        "Kill: 1022 6 22: <world> killed Ghost by MOD_TRIGGER_HURT",
+			kills:       map[string]int{"Player1 (ID 2)": 0, "Player2 (ID 3)": 0, "Ghost (ID 6)": -1},
+			totalKills:  1,
+			diagnostics: []string{DIAGNOSTIC_UNKNOWN_CLIENT},
+		},
+		{
+			name:        "World kills itself",
+			kill:        "Kill: 1022 1022 22: <world> killed <world> by MOD_TRIGGER_HURT",
+			kills:       map[string]int{"Player1 (ID 2)": 0, "Player2 (ID 3)": 0},
+			diagnostics: []string{DIAGNOSTIC_MALFORMED_LINE},
+		},
+		{
+			name:       "Player kills a player",
+			kill:       "Kill: 2 3 10: Player1 killed Player2 by MOD_RAILGUN",
+			kills:      map[string]int{"Player1 (ID 2)": 1, "Player2 (ID 3)": 0},
+			totalKills: 1,
+		},
+		{
+			name:       "Player kills themselves",
+			kill:       "Kill: 2 2 7: Player1 killed Player1 by MOD_ROCKET_SPLASH",
+			kills:      map[string]int{"Player1 (ID 2)": -1, "Player2 (ID 3)": 0},
+			totalKills: 1,
+		},
+		{
+			name:        "Player kills an unknown client",
+			kill:        "Kill: 2 6 10: Player1 killed Ghost by MOD_RAILGUN",
+			kills:       map[string]int{"Player1 (ID 2)": 1, "Player2 (ID 3)": 0, "Ghost (ID 6)": 0},
+			totalKills:  1,
+			diagnostics: []string{DIAGNOSTIC_UNKNOWN_CLIENT},
+		},
+		{
+			name:        "Player kills the world",
+			kill:        "Kill: 2 1022 10: Player1 killed <world> by MOD_RAILGUN",
+			kills:       map[string]int{"Player1 (ID 2)": 0, "Player2 (ID 3)": 0},
+			diagnostics: []string{DIAGNOSTIC_MALFORMED_LINE},
+		},
+		{
+			name:        "Unknown client kills a player",
+			kill:        "Kill: 5 3 10: Stranger killed Player2 by MOD_RAILGUN",
+			kills:       map[string]int{"Player1 (ID 2)": 0, "Player2 (ID 3)": 0, "Stranger (ID 5)": 1},
+			totalKills:  1,
+			diagnostics: []string{DIAGNOSTIC_UNKNOWN_CLIENT},
+		},
+		{
+			name:        "Unknown client kills themselves",
+			kill:        "Kill: 5 5 7: Stranger killed Stranger by MOD_ROCKET_SPLASH",
+			kills:       map[string]int{"Player1 (ID 2)": 0, "Player2 (ID 3)": 0, "Stranger (ID 5)": -1},
+			totalKills:  1,
+			diagnostics: []string{DIAGNOSTIC_UNKNOWN_CLIENT},
+		},
+		{
+			name:        "Unknown client kills an unknown client",
+			kill:        "Kill: 5 6 10: Stranger killed Ghost by MOD_RAILGUN",
+			kills:       map[string]int{"Player1 (ID 2)": 0, "Player2 (ID 3)": 0, "Stranger (ID 5)": 1, "Ghost (ID 6)": 0},
+			totalKills:  1,
+			diagnostics: []string{DIAGNOSTIC_UNKNOWN_CLIENT, DIAGNOSTIC_UNKNOWN_CLIENT},
+		},
+		{
+			name:        "Unknown client kills the world",
+			kill:        "Kill: 5 1022 10: Stranger killed <world> by MOD_RAILGUN",
+			kills:       map[string]int{"Player1 (ID 2)": 0, "Player2 (ID 3)": 0},
+			diagnostics: []string{DIAGNOSTIC_MALFORMED_LINE},
+		},
+		{
+			name:       "Player named as the world",
+			kill:       "Kill: 2 3 10: Player1 killed <world> by MOD_RAILGUN",
+			kills:      map[string]int{"Player1 (ID 2)": 1, "Player2 (ID 3)": 0},
+			totalKills: 1,
+		},
+		{
+			name:        "Unknown client named as the world",
+			kill:        "Kill: 2 6 10: Player1 killed <world> by MOD_RAILGUN",
+			kills:       map[string]int{"Player1 (ID 2)": 1, "Player2 (ID 3)": 0, "<unknown> (ID 6)": 0},
+			totalKills:  1,
+			diagnostics: []string{DIAGNOSTIC_UNKNOWN_CLIENT},
+		},
+		{
+			name:       "World named as a player",
+			kill:       "Kill: 1022 3 22: Player1 killed Player2 by MOD_TRIGGER_HURT",
+			kills:      map[string]int{"Player1 (ID 2)": 0, "Player2 (ID 3)": -1},
+			totalKills: 1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			log := "  0:00 InitGame: \\mapname\\q3dm17\n" +
+				"  0:01 ClientUserinfoChanged: 2 n\\Player1\\t\\0\n" +
+				"  0:01 ClientUserinfoChanged: 3 n\\Player2\\t\\0\n" +
+				"  0:02 " + tc.kill + "\n" +
+				"  0:03 ShutdownGame:\n"
+
+			diagnostics := &Diagnostics{}
+			report, err := New(OnDiagnostic(diagnostics.Add)).Parse(strings.NewReader(log))
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.kills, report[0]["game_1"].Kills)
+			assert.Equal(t, tc.totalKills, report[0]["game_1"].TotalKills)
+
+			kinds := make([]string, 0)
+			for _, diagnostic := range diagnostics.List() {
+				kinds = append(kinds, diagnostic.Kind)
+			}
+			if tc.diagnostics == nil {
+				tc.diagnostics = []string{}
+			}
+			assert.Equal(t, tc.diagnostics, kinds)
+		})
+	}
+}
+
+func TestKillEntitiesTeamGame(t *testing.T) {
+	log := "  0:00 InitGame: \\g_gametype\\4\\mapname\\q3ctf1\n" +
+		"  0:01 ClientUserinfoChanged: 2 n\\Player1\\t\\1\n" +
+		"  0:02 Kill: 2 6 10: Player1 killed Ghost by MOD_RAILGUN\n" +
+		"  0:03 Kill: 6 2 10: Ghost killed Player1 by MOD_RAILGUN\n" +
+		"  0:04 ShutdownGame:\n"
+
+	report, err := New().Parse(strings.NewReader(log))
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"Player1 (ID 2)": 1, "Ghost (ID 6)": 1}, report[0]["game_1"].Kills, "An unknown client is on no team")
+	assert.Nil(t, report[0]["game_1"].Sections)
+}
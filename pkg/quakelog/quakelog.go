@@ -0,0 +1,66 @@
+package quakelog
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"slices"
+)
+
+// Parser parses Quake 3 Arena server logs. It only holds its options, so the
+// same Parser can be used for several logs, one at a time or concurrently,
+// as long as its options are safe to share (WithState is not).
+type Parser struct {
+	options []Option
+}
+
+// New returns a Parser configured with the given options.
+func New(options ...Option) *Parser {
+	return &Parser{options: options}
+}
+
+// Parse reads the log from r until EOF and returns the report of every
+// finished match. A match without ShutdownGame is closed by the next
+// InitGame, while the one still in progress at the end of the log is left
+// out of the report. In Strict mode the first anomaly is returned as a
+// *DiagnosticError.
+func (p *Parser) Parse(r io.Reader) (GameReport, error) {
+	game := newGameState(p.options)
+
+	scanner := bufio.NewScanner(r)
+	for game.err == nil && scanner.Scan() {
+		game.parseLine(scanner.Text())
+	}
+
+	if game.err != nil {
+		return nil, game.err
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading log: %w", err)
+	}
+
+	return game.finish(), nil
+}
+
+// ParseEvents reads the log from r until EOF and returns every event decoded
+// from it, in the order they happened. Handlers registered with OnEvent are
+// still called.
+func (p *Parser) ParseEvents(r io.Reader) ([]Event, error) {
+	events := make([]Event, 0)
+
+	collect := func(game *gameState) {
+		handler := game.onEvent
+		game.onEvent = func(event Event, scoreboard Scoreboard) {
+			events = append(events, event)
+			if handler != nil {
+				handler(event, scoreboard)
+			}
+		}
+	}
+
+	if _, err := New(append(slices.Clone(p.options), collect)...).Parse(r); err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
@@ -0,0 +1,86 @@
+package quakelog
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testLog = `  0:00 InitGame: \mapname\q3dm17
+  0:01 ClientConnect: 2
+  0:01 ClientUserinfoChanged: 2 n\Player1\t\0
+  0:02 Kill: 1022 2 22: <world> killed Player1 by MOD_TRIGGER_HURT
+  0:03 ShutdownGame:
+  0:04 InitGame: \mapname\q3dm6
+  0:04 ClientUserinfoChanged: 2 n\Player1\t\0
+  0:05 Kill: 1022 2 22: <world> killed Player1 by MOD_TRIGGER_HURT
+`
+
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) {
+	return 0, errors.New("disk failure")
+}
+
+func TestParserParse(t *testing.T) {
+	report, err := New().Parse(strings.NewReader(testLog))
+
+	assert.NoError(t, err)
+	assert.Len(t, report, 1, "The match in progress is left out")
+	assert.Equal(t, "q3dm17", report[0]["game_1"].Map)
+	assert.Equal(t, map[string]int{"Player1 (ID 2)": -1}, report[0]["game_1"].Kills)
+
+	_, err = New().Parse(failingReader{})
+	assert.EqualError(t, err, "error reading log: disk failure")
+}
+
+func TestParserParseMatchesParseLines(t *testing.T) {
+	content, err := os.ReadFile("../../assets/qgames.log")
+	assert.NoError(t, err)
+
+	lines := make(chan string)
+	gameReport := make(chan GameReport)
+	go func() {
+		for _, line := range strings.Split(strings.TrimSuffix(string(content), "\n"), "\n") {
+			lines <- strings.TrimSuffix(line, "\r")
+		}
+		close(lines)
+	}()
+	go ParseLines(context.Background(), lines, gameReport, nil)
+
+	report, err := New().Parse(strings.NewReader(string(content)))
+
+	assert.NoError(t, err)
+	assert.Equal(t, len(<-gameReport), len(report))
+}
+
+func TestParserParseEvents(t *testing.T) {
+	handled := 0
+	parser := New(OnEvent(func(Event, Scoreboard) {
+		handled++
+	}))
+
+	events, err := parser.ParseEvents(strings.NewReader(testLog))
+
+	assert.NoError(t, err)
+	types := make([]string, 0, len(events))
+	for _, event := range events {
+		types = append(types, event.Type)
+	}
+	assert.Equal(t, []string{
+		EVENT_MATCH_START, EVENT_JOIN, EVENT_PLAYER_INFO, EVENT_KILL, EVENT_MATCH_END, EVENT_MATCH_START, EVENT_PLAYER_INFO, EVENT_KILL,
+	}, types)
+	assert.Equal(t, len(events), handled, "Handlers registered with OnEvent are still called")
+
+	// the parser can be reused
+	again, err := parser.ParseEvents(strings.NewReader(testLog))
+	assert.NoError(t, err)
+	assert.Equal(t, events, again)
+
+	_, err = parser.ParseEvents(failingReader{})
+	assert.Error(t, err)
+}
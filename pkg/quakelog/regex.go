@@ -0,0 +1,71 @@
+package quakelog
+
+import "regexp"
+
+// RegexPatterns are the regular expressions identifying each game event,
+// keyed by event type.
+var RegexPatterns = map[string]*regexp.Regexp{
+	// ^ matches the start of the line
+	// .* matches any character zero or more times
+	// InitGame: matches the string "InitGame:"
+	// $ matches the end of the line
+	"InitGame": regexp.MustCompile(`^.*InitGame: (.*)$`),
+
+	// ^ matches the start of the line
+	// .* matches any character zero or more times
+	// Kill: matches the string "Kill:"
+	// (\d+) (\d+) (\d+): matches one or more digits (3 times) followed by a colon
+	// (.+) killed (.+?) matches the killer name followed by "killed" and the victim name
+	// (?: by ?(\S*))? optionally captures the means of death after "by", which may be missing or cut off
+	// \s*$ allows trailing spaces until the end of the line
+	"Kill": regexp.MustCompile(`^.*Kill: (\d+) (\d+) (\d+): (.+) killed (.+?)(?: by ?(\S*))?\s*$`),
+
+	// ^ matches the start of the line
+	// .* matches any character zero or more times
+	// ClientUserinfoChanged: matches the string "ClientUserinfoChanged:"
+	// (\d+) matches one or more digits
+	// n\\([^\\]+)\\t = n\\ matches "n\", ([^\\]+) captures a group of characters (username) excluding the backslash, \\t ends with "\t" after the username
+	// (?:\\(\d+))? optionally captures the team following "\t\" (0 free, 1 red, 2 blue, 3 spectator)
+	"ClientUserinfoChanged": regexp.MustCompile(`^.*ClientUserinfoChanged: (\d+) n\\([^\\]+)\\t(?:\\(\d+))?`),
+
+	// ^ matches the start of the line
+	// .* matches any character zero or more times
+	// ShutdownGame: matches the string "ShutdownGame:"
+	// $ matches the end of the line
+	"ShutdownGame": regexp.MustCompile(`^.*ShutdownGame:(.*)$`),
+
+	// ^ matches the start of the line
+	// .* matches any character zero or more times
+	// ClientConnect: matches the string "ClientConnect:"
+	// (\d+) matches one or more digits (playerID)
+	// \s*$ allows trailing spaces until the end of the line
+	"ClientConnect": regexp.MustCompile(`^.*ClientConnect: (\d+)\s*$`),
+
+	// ^ matches the start of the line
+	// .* matches any character zero or more times
+	// ClientDisconnect: matches the string "ClientDisconnect:"
+	// (\d+) matches one or more digits (playerID)
+	// \s*$ allows trailing spaces until the end of the line
+	"ClientDisconnect": regexp.MustCompile(`^.*ClientDisconnect: (\d+)\s*$`),
+}
+
+// eventOrder is the order the RegexPatterns are tried in for the lines that do
+// not name their entry, since some lines match more than one of them.
+var eventOrder = []string{INIT_GAME, KILL, USER_INFO, END_GAME, CLIENT_CONNECT, CLIENT_DISCONNECT}
+
+// LineEntry captures the time and the name of the entry of a log line, which
+// is empty for the separator lines.
+// ^\s* skips the indentation of the line
+// (\d+:\d{2}) captures the time of the entry (minutes:seconds)
+// (?:(\w+):|-+\s*$) captures the name of the entry before its colon, or matches a separator of dashes
+var LineEntry = regexp.MustCompile(`^\s*(\d+:\d{2}) (?:(\w+):|-+\s*$)`)
+
+// LineSplice finds the entries starting in the middle of a line.
+// \s(\d+:\d{2}) captures the time of the entry after a space
+// (?:(\w+):|-+\s*$) captures the name of the entry before its colon, or matches a separator of dashes
+var LineSplice = regexp.MustCompile(`\s(\d+:\d{2}) (?:(\w+):|-+\s*$)`)
+
+// LineTime captures the time at the beginning of a log line.
+// ^\s* skips the indentation of the line
+// (\d+:\d{2}) captures the time of the event (minutes:seconds)
+var LineTime = regexp.MustCompile(`^\s*(\d+:\d{2}) `)
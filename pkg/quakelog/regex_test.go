@@ -1,4 +1,4 @@
-package logparser
+package quakelog
 
 import (
 	"testing"
@@ -45,9 +45,10 @@ func TestRegexPatterns(t *testing.T) {
 			eventType: USER_INFO,
 			want:      true,
 			matches: []string{
-				"20:34 ClientUserinfoChanged: 2 n\\Isgalamido\\t",
+				"20:34 ClientUserinfoChanged: 2 n\\Isgalamido\\t\\0",
 				"2",
 				"Isgalamido",
+				"0",
 			},
 		},
 		{
@@ -60,6 +61,33 @@ func TestRegexPatterns(t *testing.T) {
 				"",
 			},
 		},
+		{
+			name:      "Valid ClientConnect",
+			line:      " 20:38 ClientConnect: 2",
+			eventType: CLIENT_CONNECT,
+			want:      true,
+			matches: []string{
+				" 20:38 ClientConnect: 2",
+				"2",
+			},
+		},
+		{
+			name:      "Valid ClientDisconnect",
+			line:      " 21:10 ClientDisconnect: 2 ",
+			eventType: CLIENT_DISCONNECT,
+			want:      true,
+			matches: []string{
+				" 21:10 ClientDisconnect: 2 ",
+				"2",
+			},
+		},
+		{
+			name:      "Invalid ClientConnect format",
+			line:      "20:38 ClientConnect: player",
+			eventType: CLIENT_CONNECT,
+			want:      false,
+			matches:   nil,
+		},
 		{
 			name:      "Invalid Kill format",
 			line:      "Kill: invalid format",
@@ -95,9 +123,52 @@ func TestRegexPatterns(t *testing.T) {
 			eventType: USER_INFO,
 			want:      true,
 			matches: []string{
-				"20:34 ClientUserinfoChanged: 2 n\\Player!@#$%\\t",
+				"20:34 ClientUserinfoChanged: 2 n\\Player!@#$%\\t\\0",
 				"2",
 				"Player!@#$%",
+				"0",
+			},
+		},
+		{
+			name:      "UserInfo without team",
+			line:      "20:34 ClientUserinfoChanged: 2 n\\Isgalamido\\t",
+			eventType: USER_INFO,
+			want:      true,
+			matches: []string{
+				"20:34 ClientUserinfoChanged: 2 n\\Isgalamido\\t",
+				"2",
+				"Isgalamido",
+				"",
+			},
+		},
+		{
+			name:      "Kill without means of death",
+			line:      "20:54 Kill: 2 3 10: Isgalamido killed Dono da Bola",
+			eventType: KILL,
+			want:      true,
+			matches: []string{
+				"20:54 Kill: 2 3 10: Isgalamido killed Dono da Bola",
+				"2",
+				"3",
+				"10",
+				"Isgalamido",
+				"Dono da Bola",
+				"",
+			},
+		},
+		{
+			name:      "Kill with names containing by",
+			line:      "20:54 Kill: 2 3 10: Stand by Me killed Dono by Bola by MOD_RAIL ",
+			eventType: KILL,
+			want:      true,
+			matches: []string{
+				"20:54 Kill: 2 3 10: Stand by Me killed Dono by Bola by MOD_RAIL ",
+				"2",
+				"3",
+				"10",
+				"Stand by Me",
+				"Dono by Bola",
+				"MOD_RAIL",
 			},
 		},
 		{
@@ -150,3 +221,17 @@ func TestRegexPatterns(t *testing.T) {
 		})
 	}
 }
+
+func TestLineTime(t *testing.T) {
+	assert.Equal(t, "20:38", parseTime(" 20:38 ClientConnect: 2"))
+	assert.Equal(t, "120:05", parseTime("120:05 Kill: 2 3 7: A killed B by MOD_ROCKET"))
+	assert.Equal(t, "", parseTime("ClientConnect: 2"))
+}
+
+func TestLineEntry(t *testing.T) {
+	assert.Equal(t, []string{"  1:47 Item:", "1:47", "Item"},
+		LineEntry.FindStringSubmatch("  1:47 Item: 2 weapon_rocketlauncher"))
+	assert.Equal(t, []string{"  0:00 ------", "0:00", ""}, LineEntry.FindStringSubmatch("  0:00 ------"))
+	assert.Nil(t, LineEntry.FindStringSubmatch(" 26  0:00 ------"))
+	assert.Nil(t, LineEntry.FindStringSubmatch("  0:00 ------ InitGame:"))
+}
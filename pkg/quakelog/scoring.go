@@ -0,0 +1,118 @@
+package quakelog
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Game types of the InitGame g_gametype setting played in teams.
+const (
+	GAMETYPE_TEAM = 3
+	GAMETYPE_CTF  = 4
+)
+
+// SECTION_TEAM_KILLS is the section of the reports of team games listing the
+// players who killed teammates.
+const SECTION_TEAM_KILLS = "team_kills"
+
+// Teams of the ClientUserinfoChanged t setting.
+const (
+	TEAM_FREE = iota
+	TEAM_RED
+	TEAM_BLUE
+	TEAM_SPECTATOR
+)
+
+// ScoringRules are the points each kill adds to (or, when negative, removes
+// from) the score of the players, which the reports list in Kills.
+type ScoringRules struct {
+	// KillPoints go to the killer of another player.
+	KillPoints int `json:"kill_points"`
+	// WeaponPoints replace KillPoints for the kills made with the given means
+	// of death.
+	WeaponPoints map[string]int `json:"weapon_points,omitempty"`
+	// SuicidePoints go to the players who killed themselves.
+	SuicidePoints int `json:"suicide_points"`
+	// WorldDeathPoints go to the players killed by <world>.
+	WorldDeathPoints int `json:"world_death_points"`
+	// TeamKillPoints go to the killer of a teammate in team game types. When
+	// nil, a team kill scores as a regular kill.
+	TeamKillPoints *int `json:"team_kill_points"`
+}
+
+// DefaultScoringRules are the rules of the original challenge: a kill adds
+// one point to the killer, while a suicide or a death caused by <world>
+// removes one point from the victim. Killing a teammate removes one point
+// from the killer.
+func DefaultScoringRules() ScoringRules {
+	teamKillPoints := -1
+	return ScoringRules{
+		KillPoints:       1,
+		SuicidePoints:    -1,
+		WorldDeathPoints: -1,
+		TeamKillPoints:   &teamKillPoints,
+	}
+}
+
+// WithScoringRules scores the kills with rules instead of
+// DefaultScoringRules.
+func WithScoringRules(rules ScoringRules) Option {
+	return func(game *gameState) {
+		game.scoring = rules
+	}
+}
+
+func (rules ScoringRules) killPoints(method string) int {
+	if points, ok := rules.WeaponPoints[method]; ok {
+		return points
+	}
+	return rules.KillPoints
+}
+
+// scoreKill applies the scoring rules to a kill and reports whether the
+// killer killed a teammate.
+func (game *gameState) scoreKill(killer, victim entity, method string) bool {
+	switch {
+	case killer.isWorld():
+		victim.player.kills += game.scoring.WorldDeathPoints
+	case killer.ID == victim.ID:
+		victim.player.kills += game.scoring.SuicidePoints
+	case game.isTeamKill(killer, victim):
+		killer.player.teamKills++
+		if game.scoring.TeamKillPoints != nil {
+			killer.player.kills += *game.scoring.TeamKillPoints
+		} else {
+			killer.player.kills += game.scoring.killPoints(method)
+		}
+		return true
+	default:
+		killer.player.kills += game.scoring.killPoints(method)
+	}
+
+	return false
+}
+
+func (game *gameState) isTeamKill(killer, victim entity) bool {
+	return game.teamGame && killer.player.team == victim.player.team &&
+		(killer.player.team == TEAM_RED || killer.player.team == TEAM_BLUE)
+}
+
+// teamKills returns the team_kills section of a team game: the number of
+// teammates killed by each player who killed any.
+func (game *gameState) teamKills() map[string]int {
+	teamKills := make(map[string]int)
+	for ID, player := range game.players {
+		if player.teamKills > 0 {
+			teamKills[fmt.Sprintf("%s (ID %d)", player.name, ID)] = player.teamKills
+		}
+	}
+
+	return teamKills
+}
+
+// isTeamGameType reports whether the g_gametype setting of InitGame is played
+// in teams.
+func isTeamGameType(gameType string) bool {
+	value, err := strconv.Atoi(gameType)
+	return err == nil && (value == GAMETYPE_TEAM || value == GAMETYPE_CTF)
+}
@@ -0,0 +1,169 @@
+package quakelog
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScoringRules(t *testing.T) {
+	ffa := "  0:00 InitGame: \\g_gametype\\0\\mapname\\q3dm17\n" +
+		"  0:01 ClientUserinfoChanged: 2 n\\Player1\\t\\0\n" +
+		"  0:01 ClientUserinfoChanged: 3 n\\Player2\\t\\0\n" +
+		"  0:02 Kill: 2 3 10: Player1 killed Player2 by MOD_RAILGUN\n" +
+		"  0:03 Kill: 2 3 7: Player1 killed Player2 by MOD_ROCKET_SPLASH\n" +
+		"  0:04 Kill: 3 3 7: Player2 killed Player2 by MOD_ROCKET_SPLASH\n" +
+		"  0:05 Kill: 1022 2 22: <world> killed Player1 by MOD_TRIGGER_HURT\n" +
+		"  0:06 ShutdownGame:\n"
+
+	ctf := "  0:00 InitGame: \\g_gametype\\4\\mapname\\q3ctf1\n" +
+		"  0:01 ClientUserinfoChanged: 2 n\\Player1\\t\\1\n" +
+		"  0:01 ClientUserinfoChanged: 3 n\\Player2\\t\\1\n" +
+		"  0:01 ClientUserinfoChanged: 4 n\\Player3\\t\\2\n" +
+		"  0:02 Kill: 2 3 10: Player1 killed Player2 by MOD_RAILGUN\n" +
+		"  0:03 Kill: 2 4 10: Player1 killed Player3 by MOD_RAILGUN\n" +
+		"  0:04 ShutdownGame:\n"
+
+	teamKill := -1
+
+	tests := []struct {
+		name     string
+		log      string
+		rules    *ScoringRules
+		expected map[string]int
+	}{
+		{
+			name:     "Default rules",
+			log:      ffa,
+			expected: map[string]int{"Player1 (ID 2)": 1, "Player2 (ID 3)": -1},
+		},
+		{
+			name:     "No penalty",
+			log:      ffa,
+			rules:    &ScoringRules{KillPoints: 1},
+			expected: map[string]int{"Player1 (ID 2)": 2, "Player2 (ID 3)": 0},
+		},
+		{
+			name:     "Penalty only for suicides",
+			log:      ffa,
+			rules:    &ScoringRules{KillPoints: 1, SuicidePoints: -1},
+			expected: map[string]int{"Player1 (ID 2)": 2, "Player2 (ID 3)": -1},
+		},
+		{
+			name:     "Weapon weights",
+			log:      ffa,
+			rules:    &ScoringRules{KillPoints: 1, WeaponPoints: map[string]int{MOD_RAILGUN: 3}},
+			expected: map[string]int{"Player1 (ID 2)": 4, "Player2 (ID 3)": 0},
+		},
+		{
+			name:     "Team kill penalty by default",
+			log:      ctf,
+			expected: map[string]int{"Player1 (ID 2)": 0, "Player2 (ID 3)": 0, "Player3 (ID 4)": 0},
+		},
+		{
+			name:     "Team kills score as kills without team kill points",
+			log:      ctf,
+			rules:    &ScoringRules{KillPoints: 1},
+			expected: map[string]int{"Player1 (ID 2)": 2, "Player2 (ID 3)": 0, "Player3 (ID 4)": 0},
+		},
+		{
+			name:     "Team kill penalty",
+			log:      ctf,
+			rules:    &ScoringRules{KillPoints: 1, TeamKillPoints: &teamKill},
+			expected: map[string]int{"Player1 (ID 2)": 0, "Player2 (ID 3)": 0, "Player3 (ID 4)": 0},
+		},
+		{
+			name:     "Teammates on the free team",
+			log:      strings.ReplaceAll(ctf, "\\t\\1", "\\t\\0"),
+			expected: map[string]int{"Player1 (ID 2)": 2, "Player2 (ID 3)": 0, "Player3 (ID 4)": 0},
+		},
+		{
+			name:     "Team kill penalty outside team game types",
+			log:      strings.Replace(ctf, "\\g_gametype\\4", "\\g_gametype\\0", 1),
+			rules:    &ScoringRules{KillPoints: 1, TeamKillPoints: &teamKill},
+			expected: map[string]int{"Player1 (ID 2)": 2, "Player2 (ID 3)": 0, "Player3 (ID 4)": 0},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			options := make([]Option, 0)
+			if tc.rules != nil {
+				options = append(options, WithScoringRules(*tc.rules))
+			}
+
+			report, err := New(options...).Parse(strings.NewReader(tc.log))
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, report[0]["game_1"].Kills)
+		})
+	}
+}
+
+func TestScoringRulesResumedTeams(t *testing.T) {
+	teamKill := -1
+	rules := WithScoringRules(ScoringRules{KillPoints: 1, TeamKillPoints: &teamKill})
+
+	state := &State{}
+	_, err := New(rules, WithState(state)).Parse(strings.NewReader("  0:00 InitGame: \\g_gametype\\3\n" +
+		"  0:01 ClientUserinfoChanged: 2 n\\Player1\\t\\2\n" +
+		"  0:01 ClientUserinfoChanged: 3 n\\Player2\\t\\2\n"))
+	assert.NoError(t, err)
+
+	report, err := New(rules, WithState(state)).Parse(strings.NewReader("  0:02 Kill: 2 3 10: Player1 killed Player2 by MOD_RAILGUN\n" +
+		"  0:03 ShutdownGame:\n"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, -1, report[0]["game_1"].Kills["Player1 (ID 2)"])
+}
+
+func TestTeamKills(t *testing.T) {
+	log := "  0:00 InitGame: \\g_gametype\\3\\mapname\\q3tourney2\n" +
+		"  0:01 ClientUserinfoChanged: 2 n\\Player1\\t\\1\n" +
+		"  0:01 ClientUserinfoChanged: 3 n\\Player2\\t\\1\n" +
+		"  0:01 ClientUserinfoChanged: 4 n\\Player3\\t\\2\n" +
+		"  0:02 Kill: 2 3 10: Player1 killed Player2 by MOD_RAILGUN\n" +
+		"  0:03 Kill: 2 3 10: Player1 killed Player2 by MOD_RAILGUN\n" +
+		"  0:04 Kill: 4 2 10: Player3 killed Player1 by MOD_RAILGUN\n" +
+		"  0:05 ClientUserinfoChanged: 4 n\\Player3\\t\\1\n" +
+		"  0:06 Kill: 4 3 7: Player3 killed Player2 by MOD_ROCKET_SPLASH\n" +
+		"  0:07 ShutdownGame:\n" +
+		"  0:08 InitGame: \\g_gametype\\3\\mapname\\q3tourney2\n" +
+		"  0:09 ClientUserinfoChanged: 2 n\\Player1\\t\\1\n" +
+		"  0:09 ClientUserinfoChanged: 4 n\\Player3\\t\\2\n" +
+		"  0:10 Kill: 4 2 10: Player3 killed Player1 by MOD_RAILGUN\n" +
+		"  0:11 ShutdownGame:\n"
+
+	teamKills := make([]bool, 0)
+	report, err := New(OnEvent(func(event Event, _ Scoreboard) {
+		if event.Type == EVENT_KILL {
+			teamKills = append(teamKills, event.Kill.TeamKill)
+		}
+	})).Parse(strings.NewReader(log))
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		SECTION_TEAM_KILLS: map[string]int{"Player1 (ID 2)": 2, "Player3 (ID 4)": 1},
+	}, report[0]["game_1"].Sections)
+	assert.Equal(t, map[string]int{"Player1 (ID 2)": -2, "Player2 (ID 3)": 0, "Player3 (ID 4)": 0},
+		report[0]["game_1"].Kills)
+	assert.Nil(t, report[1]["game_2"].Sections, "A match without team kills has no team_kills section")
+	assert.Equal(t, []bool{true, true, false, true, false}, teamKills)
+}
+
+func TestTeamKillsResumed(t *testing.T) {
+	state := &State{}
+	_, err := New(WithState(state)).Parse(strings.NewReader("  0:00 InitGame: \\g_gametype\\4\n" +
+		"  0:01 ClientUserinfoChanged: 2 n\\Player1\\t\\2\n" +
+		"  0:01 ClientUserinfoChanged: 3 n\\Player2\\t\\2\n" +
+		"  0:02 Kill: 2 3 10: Player1 killed Player2 by MOD_RAILGUN\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, state.Players[2].TeamKills)
+
+	report, err := New(WithState(state)).Parse(strings.NewReader("  0:03 Kill: 2 3 10: Player1 killed Player2 by MOD_RAILGUN\n" +
+		"  0:04 ShutdownGame:\n"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"Player1 (ID 2)": 2}, report[0]["game_1"].Sections[SECTION_TEAM_KILLS])
+}
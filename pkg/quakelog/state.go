@@ -0,0 +1,70 @@
+package quakelog
+
+// State is a serializable snapshot of the parser, used to resume parsing a
+// growing log from where a previous run stopped, including a match that was
+// still in progress.
+type State struct {
+	TotalGames  int                 `json:"total_games"`
+	Lines       int                 `json:"lines,omitempty"`
+	GameStarted bool                `json:"game_started"`
+	TeamGame    bool                `json:"team_game,omitempty"`
+	Players     map[int]PlayerState `json:"players,omitempty"`
+	Match       MatchReport         `json:"match"`
+	Orphans     []OrphanLine        `json:"orphans,omitempty"`
+	// Fingerprinted is the number of events of the match hashed into its
+	// Fingerprint
+	Fingerprinted int `json:"fingerprinted,omitempty"`
+	// Diagnostics are the anomalies of the match, kept with ReportDiagnostics
+	Diagnostics []Diagnostic `json:"diagnostics,omitempty"`
+}
+
+// PlayerState is the score of a player in the match in progress, with the
+// number of teammates they killed.
+type PlayerState struct {
+	Name      string `json:"name"`
+	Kills     int    `json:"kills"`
+	Team      int    `json:"team,omitempty"`
+	TeamKills int    `json:"team_kills,omitempty"`
+}
+
+func (game *gameState) restore(state State) {
+	game.totalGames = state.TotalGames
+	game.lineNumber = state.Lines
+	game.gameStarted = state.GameStarted
+	game.orphans = state.Orphans
+	if !state.GameStarted {
+		return
+	}
+
+	game.matchReport = state.Match
+	game.teamGame = state.TeamGame
+	game.fingerprinted = state.Fingerprinted
+	game.diagnostics = state.Diagnostics
+	game.players = make(map[int]*playerInfo, len(state.Players))
+	for ID, player := range state.Players {
+		game.players[ID] = &playerInfo{name: player.Name, kills: player.Kills, team: player.Team, teamKills: player.TeamKills}
+	}
+}
+
+func (game *gameState) snapshot() State {
+	state := State{
+		TotalGames:  game.totalGames,
+		Lines:       game.lineNumber,
+		GameStarted: game.gameStarted,
+		Orphans:     game.orphans,
+	}
+	if !game.gameStarted {
+		return state
+	}
+
+	state.Match = game.matchReport
+	state.TeamGame = game.teamGame
+	state.Fingerprinted = game.fingerprinted
+	state.Diagnostics = game.diagnostics
+	state.Players = make(map[int]PlayerState, len(game.players))
+	for ID, player := range game.players {
+		state.Players[ID] = PlayerState{Name: player.name, Kills: player.kills, Team: player.team, TeamKills: player.teamKills}
+	}
+
+	return state
+}
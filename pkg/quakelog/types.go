@@ -0,0 +1,114 @@
+package quakelog
+
+// Means of death of baseq3 and missionpack, as written at the end of the
+// Kill lines. BuiltinMeans describes them.
+const (
+	MOD_UNKNOWN        = "MOD_UNKNOWN"
+	MOD_SHOTGUN        = "MOD_SHOTGUN"
+	MOD_GAUNTLET       = "MOD_GAUNTLET"
+	MOD_MACHINEGUN     = "MOD_MACHINEGUN"
+	MOD_GRENADE        = "MOD_GRENADE"
+	MOD_GRENADE_SPLASH = "MOD_GRENADE_SPLASH"
+	MOD_ROCKET         = "MOD_ROCKET"
+	MOD_ROCKET_SPLASH  = "MOD_ROCKET_SPLASH"
+	MOD_PLASMA         = "MOD_PLASMA"
+	MOD_PLASMA_SPLASH  = "MOD_PLASMA_SPLASH"
+	MOD_RAILGUN        = "MOD_RAILGUN"
+	MOD_LIGHTNING      = "MOD_LIGHTNING"
+	MOD_BFG            = "MOD_BFG"
+	MOD_BFG_SPLASH     = "MOD_BFG_SPLASH"
+	MOD_WATER          = "MOD_WATER"
+	MOD_SLIME          = "MOD_SLIME"
+	MOD_LAVA           = "MOD_LAVA"
+	MOD_CRUSH          = "MOD_CRUSH"
+	MOD_TELEFRAG       = "MOD_TELEFRAG"
+	MOD_FALLING        = "MOD_FALLING"
+	MOD_SUICIDE        = "MOD_SUICIDE"
+	MOD_TARGET_LASER   = "MOD_TARGET_LASER"
+	MOD_TRIGGER_HURT   = "MOD_TRIGGER_HURT"
+	MOD_NAIL           = "MOD_NAIL"
+	MOD_CHAINGUN       = "MOD_CHAINGUN"
+	MOD_PROXIMITY_MINE = "MOD_PROXIMITY_MINE"
+	MOD_KAMIKAZE       = "MOD_KAMIKAZE"
+	MOD_JUICED         = "MOD_JUICED"
+	MOD_GRAPPLE        = "MOD_GRAPPLE"
+)
+
+// Game events, named after the log entries they are decoded from
+const (
+	INIT_GAME         = "InitGame"
+	KILL              = "Kill"
+	USER_INFO         = "ClientUserinfoChanged"
+	END_GAME          = "ShutdownGame"
+	CLIENT_CONNECT    = "ClientConnect"
+	CLIENT_DISCONNECT = "ClientDisconnect"
+)
+
+// WORLD is the killer name of deaths not caused by a player, like falling or
+// drowning.
+const WORLD = "<world>"
+
+// MatchReport is the summary of a single match. Players and the keys of Kills
+// are formatted as "name (ID n)", Players being sorted by client ID unless
+// WithPlayerOrder says otherwise, and KillsByMeans lists every means of death
+// of the MeansRegistry, including the ones with no kills. Source is only set on reports
+// merged from several log files, and Sections holds the statistics of the
+// handlers registered with WithHandler, keyed by their name, along with the
+// team_kills of team games. Integrity is only set on damaged matches, and
+// Fingerprint only with WithFingerprints.
+type MatchReport struct {
+	Map          string         `json:"map,omitempty"`
+	TotalKills   int            `json:"total_kills"`
+	Players      []string       `json:"players"`
+	Kills        map[string]int `json:"kills"`
+	KillsByMeans map[string]int `json:"kills_by_means"`
+	Source       string         `json:"source,omitempty"`
+	Sections     map[string]any `json:"sections,omitempty"`
+	Integrity    *Integrity     `json:"integrity,omitempty"`
+	Fingerprint  string         `json:"fingerprint,omitempty"`
+}
+
+// GameReport is the report of a whole log: one single-key map per match, from
+// its name (game_1, game_2, ...) to its report, in the order they were played.
+type GameReport []map[string]MatchReport
+
+type playerInfo struct {
+	name      string
+	kills     int
+	team      int
+	teamKills int
+}
+
+type gameState struct {
+	totalGames        int
+	gameStarted       bool
+	players           map[int]*playerInfo
+	matchReport       MatchReport
+	gameReport        GameReport
+	onMatchEnd        func(map[string]MatchReport)
+	onEvent           func(Event, Scoreboard)
+	onDiagnostic      func(Diagnostic)
+	handlers          []namedHandler
+	active            []EventHandler
+	scoring           ScoringRules
+	means             *MeansRegistry
+	teamGame          bool
+	time              string
+	line              string
+	lastTime          string
+	lineNumber        int
+	strict            bool
+	ignored           map[string]bool
+	err               error
+	state             *State
+	orphanPolicy      string
+	orphans           []OrphanLine
+	unscoped          *unscopedState
+	inUnscoped        bool
+	attaching         bool
+	playerOrder       string
+	fingerprints      bool
+	fingerprinted     int
+	reportDiagnostics bool
+	diagnostics       []Diagnostic
+}